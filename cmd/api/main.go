@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"strings"
 
 	"be-job-portal/internal/config"
 	"be-job-portal/internal/delivery/http"
@@ -9,11 +11,19 @@ import (
 	"be-job-portal/internal/repository"
 	"be-job-portal/internal/usecase"
 	"be-job-portal/pkg/database"
+	"be-job-portal/pkg/mailer"
+	"be-job-portal/pkg/oidc"
+	"be-job-portal/pkg/scheduler"
+	"be-job-portal/pkg/utils"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// schedulerLockKey names the Postgres advisory lock that elects the instance running
+// background jobs.
+const schedulerLockKey = 0x6a6f6273
+
 func main() {
 	// Load Config
 	cfg, err := config.LoadConfig()
@@ -25,11 +35,21 @@ func main() {
 	db := database.ConnectDB(cfg)
 
 	// Auto Migrate
-	db.AutoMigrate(&domain.User{}, &domain.Job{}, &domain.Application{}, &domain.SeekerProfile{}, &domain.CompanyProfile{}, &domain.Experience{}, &domain.Education{})
+	if err := repository.Migrate(db); err != nil {
+		log.Fatal("Failed to migrate database: ", err)
+	}
+
+	// The first admin has to be promoted from configuration; later ones can be made through the API.
+	if cfg.AdminEmail != "" {
+		db.Model(&domain.User{}).Where("email = ?", cfg.AdminEmail).Update("role", domain.RoleAdmin)
+	}
 
 	// Init Router
 	r := gin.Default()
 
+	// Request IDs
+	r.Use(utils.RequestID())
+
 	// CORS Configuration
 	r.Use(cors.Default())
 
@@ -38,23 +58,103 @@ func main() {
 	jobRepo := repository.NewJobRepository(db)
 	appRepo := repository.NewApplicationRepository(db)
 	profileRepo := repository.NewProfileRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	oauthStateRepo := repository.NewOAuthStateRepository(db)
+	identityRepo := repository.NewUserIdentityRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+
+	var loginAttemptRepo domain.LoginAttemptRepository
+	if cfg.LoginAttemptStore == "memory" {
+		loginAttemptRepo = repository.NewMemoryLoginAttemptRepository()
+	} else {
+		loginAttemptRepo = repository.NewLoginAttemptRepository(db)
+	}
+
+	// Mail
+	mailSender, err := mailer.New(cfg.MailDriver, cfg.MailFilePath)
+	if err != nil {
+		log.Fatal("Failed to init mailer: ", err)
+	}
+
+	// Login Providers
+	var providers []*oidc.Provider
+	for _, p := range cfg.OIDCProviders {
+		providers = append(providers, oidc.NewProvider(oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+			AuthURL:      p.AuthURL,
+			TokenURL:     p.TokenURL,
+			JWKSURL:      p.JWKSURL,
+		}, nil))
+	}
+	providerRegistry := oidc.NewRegistry(providers...)
+
+	// Token Signing Keys
+	var verificationKeys []string
+	for _, path := range strings.Split(cfg.JWTVerificationKeys, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			verificationKeys = append(verificationKeys, path)
+		}
+	}
+	// A generated key differs on every replica and every restart, so it is opt-in for development.
+	if cfg.JWTSigningKey == "" && !cfg.JWTEphemeralKey {
+		log.Fatal("JWT_SIGNING_KEY is not set; point it at a PEM private key, or set JWT_EPHEMERAL_KEY=true for a throwaway development key")
+	}
+	keySet, err := utils.LoadKeySet(cfg.JWTSigningKey, verificationKeys)
+	if err != nil {
+		log.Fatal("Failed to load signing keys: ", err)
+	}
+	if cfg.JWTSigningKey == "" {
+		log.Println("JWT_EPHEMERAL_KEY is set, using an ephemeral key; tokens will not survive a restart")
+	}
+
+	// Audit Trail
+	auditUsecase := usecase.NewAuditUsecase(auditRepo)
+	r.Use(utils.AuditImpersonation(auditUsecase))
 
 	// Usecases
-	authUsecase := usecase.NewAuthUsecase(userRepo, cfg)
-	jobUsecase := usecase.NewJobUsecase(jobRepo)
-	appUsecase := usecase.NewApplicationUsecase(appRepo, jobRepo)
-	profileUsecase := usecase.NewProfileUsecase(profileRepo)
+	authUsecase := usecase.NewAuthUsecase(userRepo, sessionRepo, userTokenRepo, recoveryCodeRepo, loginAttemptRepo, oauthStateRepo, identityRepo, mailSender, providerRegistry, keySet, auditUsecase, cfg)
+	jobUsecase := usecase.NewJobUsecase(jobRepo, userRepo, orgRepo, mailSender, auditUsecase, cfg)
+	appUsecase := usecase.NewApplicationUsecase(appRepo, jobRepo, orgRepo, profileRepo, auditUsecase)
+	profileUsecase := usecase.NewProfileUsecase(profileRepo, orgRepo, userRepo, auditUsecase)
+	adminUsecase := usecase.NewAdminUsecase(userRepo, sessionRepo, keySet, auditUsecase, cfg)
+	orgUsecase := usecase.NewOrganizationUsecase(orgRepo, userRepo, mailSender, cfg)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo, orgRepo)
+
+	// Background Jobs
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("Failed to get database handle: ", err)
+	}
+	scheduler.New(sqlDB, schedulerLockKey, cfg.SchedulerInterval,
+		scheduler.Task{Name: "close expired jobs", Run: jobUsecase.CloseExpiredJobs},
+		scheduler.Task{Name: "send job expiry reminders", Run: jobUsecase.SendExpiryReminders},
+	).Start(context.Background())
 
-	// Handlers
 	// Handlers
 	authHandler := http.NewAuthHandler(authUsecase)
 	jobHandler := http.NewJobHandler(jobUsecase)
 	appHandler := http.NewApplicationHandler(appUsecase)
 	profileHandler := http.NewProfileHandler(profileUsecase, userRepo)
 	dashboardHandler := http.NewDashboardHandler(appUsecase)
+	adminHandler := http.NewAdminHandler(adminUsecase, auditUsecase)
+	orgHandler := http.NewOrganizationHandler(orgUsecase)
+	apiKeyHandler := http.NewAPIKeyHandler(apiKeyUsecase)
+	jwksHandler := http.NewJWKSHandler(keySet)
+
+	// Middlewares
+	authMiddleware := utils.AuthMiddleware(keySet, authUsecase, apiKeyUsecase)
 
 	// Register Routes
-	http.RegisterRoutes(r, authHandler, jobHandler, appHandler, profileHandler, dashboardHandler)
+	http.RegisterRoutes(r, authMiddleware, authHandler, jobHandler, appHandler, profileHandler, dashboardHandler, orgHandler, apiKeyHandler, adminHandler, jwksHandler)
 
 	// Run Server
 	r.Run(":" + cfg.ServerPort)
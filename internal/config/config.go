@@ -1,26 +1,94 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	DBHost             string `mapstructure:"DB_HOST"`
-	DBUser             string `mapstructure:"DB_USER"`
-	DBPassword         string `mapstructure:"DB_PASSWORD"`
-	DBName             string `mapstructure:"DB_NAME"`
-	DBPort             string `mapstructure:"DB_PORT"`
-	ServerPort         string `mapstructure:"SERVER_PORT"`
-	JWTSecret          string `mapstructure:"JWT_SECRET"`
-	GoogleClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
-	GoogleClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
-	GoogleRedirectURL  string `mapstructure:"GOOGLE_REDIRECT_URL"`
+	DBHost              string        `mapstructure:"DB_HOST"`
+	DBUser              string        `mapstructure:"DB_USER"`
+	DBPassword          string        `mapstructure:"DB_PASSWORD"`
+	DBName              string        `mapstructure:"DB_NAME"`
+	DBPort              string        `mapstructure:"DB_PORT"`
+	ServerPort          string        `mapstructure:"SERVER_PORT"`
+	AppBaseURL          string        `mapstructure:"APP_BASE_URL"`
+	JWTSigningKey       string        `mapstructure:"JWT_SIGNING_KEY"`
+	JWTVerificationKeys string        `mapstructure:"JWT_VERIFICATION_KEYS"`
+	JWTEphemeralKey     bool          `mapstructure:"JWT_EPHEMERAL_KEY"`
+	AccessTokenTTL      time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL     time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+	PasswordResetTTL    time.Duration `mapstructure:"PASSWORD_RESET_TTL"`
+	EmailVerifyTTL      time.Duration `mapstructure:"EMAIL_VERIFY_TTL"`
+	LoginAttemptStore   string        `mapstructure:"LOGIN_ATTEMPT_STORE"`
+	LoginMaxFailures    int           `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginMaxFailuresIP  int           `mapstructure:"LOGIN_MAX_FAILURES_PER_IP"`
+	LoginLockout        time.Duration `mapstructure:"LOGIN_LOCKOUT"`
+	LoginMaxLockout     time.Duration `mapstructure:"LOGIN_MAX_LOCKOUT"`
+	LoginFailureWindow  time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	TOTPIssuer          string        `mapstructure:"TOTP_ISSUER"`
+	MailDriver          string        `mapstructure:"MAIL_DRIVER"`
+	MailFilePath        string        `mapstructure:"MAIL_FILE_PATH"`
+	GoogleClientID      string        `mapstructure:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret  string        `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GoogleRedirectURL   string        `mapstructure:"GOOGLE_REDIRECT_URL"`
+	GoogleIssuer        string        `mapstructure:"GOOGLE_ISSUER"`
+	GoogleAuthURL       string        `mapstructure:"GOOGLE_AUTH_URL"`
+	GoogleTokenURL      string        `mapstructure:"GOOGLE_TOKEN_URL"`
+	GoogleJWKSURL       string        `mapstructure:"GOOGLE_JWKS_URL"`
+	OIDCProviderNames   string        `mapstructure:"OIDC_PROVIDERS"`
+	OIDCStateTTL        time.Duration `mapstructure:"OIDC_STATE_TTL"`
+	AdminEmail          string        `mapstructure:"ADMIN_EMAIL"`
+	OrgInviteTTL        time.Duration `mapstructure:"ORG_INVITE_TTL"`
+	ImpersonationTTL    time.Duration `mapstructure:"IMPERSONATION_TTL"`
+	SchedulerInterval   time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	JobExpiryReminder   time.Duration `mapstructure:"JOB_EXPIRY_REMINDER"`
+
+	OIDCProviders []OIDCProviderConfig `mapstructure:"-"`
+}
+
+// OIDCProviderConfig is read from OIDC_<NAME>_* variables for each name listed in
+// OIDC_PROVIDERS, e.g. OIDC_MICROSOFT_ISSUER and OIDC_MICROSOFT_CLIENT_ID.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	AuthURL      string
+	TokenURL     string
+	JWKSURL      string
 }
 
 func LoadConfig() (config Config, err error) {
 	viper.AddConfigPath(".")
 	viper.SetConfigFile("app.env")
 
+	viper.SetDefault("ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("REFRESH_TOKEN_TTL", "720h")
+	viper.SetDefault("JWT_EPHEMERAL_KEY", false)
+	viper.SetDefault("PASSWORD_RESET_TTL", "1h")
+	viper.SetDefault("EMAIL_VERIFY_TTL", "24h")
+	viper.SetDefault("APP_BASE_URL", "http://localhost:3000")
+	viper.SetDefault("LOGIN_ATTEMPT_STORE", "postgres")
+	viper.SetDefault("LOGIN_MAX_FAILURES", 5)
+	viper.SetDefault("LOGIN_MAX_FAILURES_PER_IP", 20)
+	viper.SetDefault("LOGIN_LOCKOUT", "1m")
+	viper.SetDefault("LOGIN_MAX_LOCKOUT", "1h")
+	viper.SetDefault("LOGIN_FAILURE_WINDOW", "15m")
+	viper.SetDefault("TOTP_ISSUER", "Job Portal")
+	viper.SetDefault("OIDC_STATE_TTL", "10m")
+	viper.SetDefault("ORG_INVITE_TTL", "168h")
+	viper.SetDefault("IMPERSONATION_TTL", "15m")
+	viper.SetDefault("SCHEDULER_INTERVAL", "1m")
+	viper.SetDefault("JOB_EXPIRY_REMINDER", "72h")
+	viper.SetDefault("GOOGLE_ISSUER", "https://accounts.google.com")
+	viper.SetDefault("MAIL_DRIVER", "log")
+	viper.SetDefault("MAIL_FILE_PATH", "mail.log")
+
 	viper.AutomaticEnv()
 
 	err = viper.ReadInConfig()
@@ -33,5 +101,54 @@ func LoadConfig() (config Config, err error) {
 		return
 	}
 
+	config.OIDCProviders = loadOIDCProviders(config)
+
 	return
 }
+
+func loadOIDCProviders(config Config) []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	hasGoogle := false
+
+	for _, name := range strings.Split(config.OIDCProviderNames, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		var scopes []string
+		if raw := viper.GetString(prefix + "SCOPES"); raw != "" {
+			scopes = strings.Fields(strings.ReplaceAll(raw, ",", " "))
+		}
+
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       viper.GetString(prefix + "ISSUER"),
+			ClientID:     viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "CLIENT_SECRET"),
+			RedirectURL:  viper.GetString(prefix + "REDIRECT_URL"),
+			Scopes:       scopes,
+			AuthURL:      viper.GetString(prefix + "AUTH_URL"),
+			TokenURL:     viper.GetString(prefix + "TOKEN_URL"),
+			JWKSURL:      viper.GetString(prefix + "JWKS_URL"),
+		})
+		hasGoogle = hasGoogle || name == "google"
+	}
+
+	// The GOOGLE_* variables predate the generic provider settings and keep working.
+	if !hasGoogle && config.GoogleClientID != "" {
+		providers = append(providers, OIDCProviderConfig{
+			Name:         "google",
+			Issuer:       config.GoogleIssuer,
+			ClientID:     config.GoogleClientID,
+			ClientSecret: config.GoogleClientSecret,
+			RedirectURL:  config.GoogleRedirectURL,
+			AuthURL:      config.GoogleAuthURL,
+			TokenURL:     config.GoogleTokenURL,
+			JWKSURL:      config.GoogleJWKSURL,
+		})
+	}
+
+	return providers
+}
@@ -0,0 +1,241 @@
+package http
+
+import (
+	"be-job-portal/internal/delivery/http/dto"
+	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AdminHandler struct {
+	adminUsecase domain.AdminUsecase
+	auditUsecase domain.AuditUsecase
+}
+
+func NewAdminHandler(adminUsecase domain.AdminUsecase, auditUsecase domain.AuditUsecase) *AdminHandler {
+	return &AdminHandler{
+		adminUsecase: adminUsecase,
+		auditUsecase: auditUsecase,
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	filter := domain.UserFilter{
+		Query:            c.Query("q"),
+		Role:             c.Query("role"),
+		PaginationParams: paginationParams(c),
+	}
+
+	if suspendedStr := c.Query("suspended"); suspendedStr != "" {
+		suspended, err := strconv.ParseBool(suspendedStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid filter", "suspended must be true or false")
+			return
+		}
+		filter.Suspended = &suspended
+	}
+
+	result, err := h.adminUsecase.ListUsers(c.Request.Context(), filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch users", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Users fetched successfully", result)
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	user, err := h.adminUsecase.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		respondAdminError(c, "Failed to fetch user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User fetched successfully", user)
+}
+
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	var input dto.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	adminID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	user, err := h.adminUsecase.ChangeRole(c.Request.Context(), adminID, userID, input.Role)
+	if err != nil {
+		respondAdminError(c, "Failed to change role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Role changed successfully", user)
+}
+
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	adminID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	user, err := h.adminUsecase.SuspendUser(c.Request.Context(), adminID, userID)
+	if err != nil {
+		respondAdminError(c, "Failed to suspend user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User suspended successfully", user)
+}
+
+func (h *AdminHandler) UnsuspendUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	adminID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	user, err := h.adminUsecase.UnsuspendUser(c.Request.Context(), adminID, userID)
+	if err != nil {
+		respondAdminError(c, "Failed to unsuspend user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unsuspended successfully", user)
+}
+
+func (h *AdminHandler) ForceLogout(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	adminID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	if err := h.adminUsecase.ForceLogout(c.Request.Context(), adminID, userID); err != nil {
+		respondAdminError(c, "Failed to sign out user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User signed out of all sessions", nil)
+}
+
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	adminID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	token, err := h.adminUsecase.Impersonate(c.Request.Context(), adminID, userID, clientInfo(c))
+	if err != nil {
+		switch err {
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Failed to impersonate user", "Admins cannot impersonate themselves")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Failed to impersonate user", "Admins cannot be impersonated")
+		case domain.ErrAccountSuspended:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Failed to impersonate user", "Suspended users cannot be impersonated")
+		default:
+			respondAdminError(c, "Failed to impersonate user", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Impersonation started", token)
+}
+
+// ListAuditEvents filters the audit trail by actor_id (also matching the impersonator),
+// action, target_type, target_id, request_id and an RFC 3339 from/to range.
+func (h *AdminHandler) ListAuditEvents(c *gin.Context) {
+	filter := domain.AuditFilter{
+		Action:           c.Query("action"),
+		TargetType:       c.Query("target_type"),
+		RequestID:        c.Query("request_id"),
+		PaginationParams: paginationParams(c),
+	}
+
+	for param, dst := range map[string]**uuid.UUID{"actor_id": &filter.ActorID, "target_id": &filter.TargetID} {
+		if value := c.Query(param); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Invalid filter", param+" must be a UUID")
+				return
+			}
+			*dst = &id
+		}
+	}
+	for param, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Invalid filter", param+" must be an RFC 3339 timestamp")
+				return
+			}
+			*dst = &t
+		}
+	}
+
+	result, err := h.auditUsecase.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch audit events", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Audit events fetched successfully", result)
+}
+
+func respondAdminError(c *gin.Context, message string, err error) {
+	switch err {
+	case domain.ErrNotFound:
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found", "User with given ID does not exist")
+	case domain.ErrBadRequest:
+		utils.ErrorResponse(c, http.StatusBadRequest, message, "Admins cannot change the role or suspension of their own account")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"be-job-portal/internal/delivery/http/dto"
+	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase domain.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(apiKeyUsecase domain.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var input dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	key, err := h.apiKeyUsecase.CreateKey(c.Request.Context(), actor, input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		switch err {
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Failed to create API key", "Only organization owners, admins and recruiters can create API keys")
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Failed to create API key", "Scopes must be valid and the expiry must be in the future")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create API key", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "API key created successfully; store it now, it will not be shown again", key)
+}
+
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	keys, err := h.apiKeyUsecase.ListKeys(c.Request.Context(), actor)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch API keys", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API keys fetched successfully", keys)
+}
+
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid API key ID", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	if err := h.apiKeyUsecase.RevokeKey(c.Request.Context(), actor, keyID); err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Failed to revoke API key", "API key not found")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Failed to revoke API key", "Only the key's creator or an organization owner or admin can revoke it")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke API key", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API key revoked successfully", nil)
+}
@@ -36,32 +36,63 @@ func (h *ApplicationHandler) ApplyJob(c *gin.Context) {
 		return
 	}
 
-	userID, err := utils.GetUserID(c)
+	actor, err := utils.GetActor(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
 
-	err = h.appUsecase.ApplyJob(c.Request.Context(), jobID, userID, input.ResumeURL, input.CoverLetter, input.LinkedInURL, input.PortfolioURL)
+	err = h.appUsecase.ApplyJob(c.Request.Context(), actor, jobID, input.ResumeURL, input.CoverLetter, input.LinkedInURL, input.PortfolioURL, screeningAnswers(input.Answers))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to apply for job", err.Error())
+		if respondValidationError(c, err) {
+			return
+		}
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only job seekers can apply for jobs")
+		case domain.ErrJobNotOpen:
+			utils.ErrorResponse(c, http.StatusConflict, "Job closed", "This job is not accepting applications")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to apply for job", err.Error())
+		}
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusCreated, "Applied successfully", nil)
 }
 
+func screeningAnswers(input []dto.ScreeningAnswer) []domain.ScreeningAnswer {
+	if input == nil {
+		return nil
+	}
+	answers := make([]domain.ScreeningAnswer, len(input))
+	for i, a := range input {
+		answers[i] = domain.ScreeningAnswer{
+			QuestionID: a.QuestionID,
+			Boolean:    a.Boolean,
+			Choices:    a.Choices,
+			Number:     a.Number,
+			Text:       a.Text,
+		}
+	}
+	return answers
+}
+
 func (h *ApplicationHandler) ListApplications(c *gin.Context) {
-	userID, err := utils.GetUserID(c)
+	actor, err := utils.GetActor(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
 
-	role := "SEEKER"
-
-	apps, err := h.appUsecase.ListApplications(c.Request.Context(), userID, role)
+	apps, err := h.appUsecase.ListApplications(c.Request.Context(), actor)
 	if err != nil {
+		if err == domain.ErrForbidden {
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only job seekers have applications")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch applications", err.Error())
 		return
 	}
@@ -115,19 +146,24 @@ func (h *ApplicationHandler) ListJobApplicants(c *gin.Context) {
 		return
 	}
 
-	userID, err := utils.GetUserID(c)
+	actor, err := utils.GetActor(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
 
-	apps, err := h.appUsecase.ListJobApplicants(c.Request.Context(), jobID, userID)
+	apps, err := h.appUsecase.ListJobApplicants(c.Request.Context(), actor, jobID, c.Query("sort"))
 	if err != nil {
-		if err == domain.ErrUnauthorized {
+		switch err {
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid sort", "sort must be newest or match")
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+		case domain.ErrForbidden:
 			utils.ErrorResponse(c, http.StatusForbidden, "Unauthorized", "You are not authorized to view applicants for this job")
-			return
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch applicants", err.Error())
 		}
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch applicants", err.Error())
 		return
 	}
 
@@ -135,10 +171,12 @@ func (h *ApplicationHandler) ListJobApplicants(c *gin.Context) {
 	for _, app := range apps {
 		seekerName := "Unknown"
 		seekerEmail := ""
+		seekerPhone := ""
 		if app.Seeker != nil {
 			seekerEmail = app.Seeker.Email
 			if app.Seeker.SeekerProfile != nil {
 				seekerName = app.Seeker.SeekerProfile.FullName
+				seekerPhone = app.Seeker.SeekerProfile.Phone
 			}
 		}
 
@@ -150,11 +188,17 @@ func (h *ApplicationHandler) ListJobApplicants(c *gin.Context) {
 				"id":        app.SeekerID,
 				"email":     seekerEmail,
 				"full_name": seekerName,
-				"phone":     app.Seeker.SeekerProfile.Phone,
+				"phone":     seekerPhone,
 			},
-			"resume_url":   app.ResumeURL,
-			"cover_letter": app.CoverLetter,
-			"linkedin_url": app.LinkedInURL,
+			"resume_url":          app.ResumeURL,
+			"cover_letter":        app.CoverLetter,
+			"linkedin_url":        app.LinkedInURL,
+			"job_revision_id":     app.JobRevisionID,
+			"answers":             app.Answers,
+			"flagged":             app.Flagged,
+			"failed_question_ids": app.FailedQuestionIDs,
+			"match_score":         app.MatchScore,
+			"match":               app.Match,
 		})
 	}
 
@@ -175,16 +219,18 @@ func (h *ApplicationHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	userID, err := utils.GetUserID(c)
+	actor, err := utils.GetActor(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
 
-	err = h.appUsecase.UpdateStatus(c.Request.Context(), appID, userID, input.Status)
+	err = h.appUsecase.UpdateStatus(c.Request.Context(), actor, appID, input.Status)
 	if err != nil {
 		switch err {
-		case domain.ErrUnauthorized:
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Application not found", "Application with given ID does not exist")
+		case domain.ErrForbidden:
 			utils.ErrorResponse(c, http.StatusForbidden, "Unauthorized", "You are not authorized to update this application")
 		case domain.ErrBadRequest:
 			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid status", "Status must be PENDING, PROCESS, ACCEPTED, or REJECTED")
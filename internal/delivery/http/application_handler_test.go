@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type stubApplicationUsecase struct {
+	domain.ApplicationUsecase
+	applicants []domain.Application
+}
+
+func (u *stubApplicationUsecase) ListJobApplicants(ctx context.Context, actor domain.Actor, jobID uuid.UUID, sort string) ([]domain.Application, error) {
+	return u.applicants, nil
+}
+
+// Seekers who never filled in a profile used to crash the applicant list on their phone number.
+func TestListJobApplicantsWithoutSeekerProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	withProfile := &domain.User{Email: "ada@example.com", SeekerProfile: &domain.SeekerProfile{FullName: "Ada", Phone: "+62 812"}}
+	withoutProfile := &domain.User{Email: "linus@example.com"}
+	usecase := &stubApplicationUsecase{applicants: []domain.Application{
+		{ID: uuid.New(), Seeker: withProfile},
+		{ID: uuid.New(), Seeker: withoutProfile},
+		{ID: uuid.New()},
+	}}
+
+	r := gin.New()
+	r.GET("/api/jobs/:id/applicants", func(c *gin.Context) {
+		c.Set("user_id", uuid.New())
+		c.Set("role", domain.RoleRecruiter)
+	}, NewApplicationHandler(usecase).ListJobApplicants)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/jobs/"+uuid.NewString()+"/applicants", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+
+	var body struct {
+		Data []struct {
+			Seeker struct {
+				Email    string `json:"email"`
+				FullName string `json:"full_name"`
+				Phone    string `json:"phone"`
+			} `json:"seeker"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Data) != 3 {
+		t.Fatalf("got %d applicants, want 3: %s", len(body.Data), w.Body)
+	}
+	want := []struct{ email, name, phone string }{
+		{"ada@example.com", "Ada", "+62 812"},
+		{"linus@example.com", "Unknown", ""},
+		{"", "Unknown", ""},
+	}
+	for i, exp := range want {
+		got := body.Data[i].Seeker
+		if got.Email != exp.email || got.FullName != exp.name || got.Phone != exp.phone {
+			t.Errorf("applicant %d seeker = %+v, want %+v", i, got, exp)
+		}
+	}
+}
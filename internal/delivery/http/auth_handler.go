@@ -1,13 +1,20 @@
 package http
 
 import (
+	"crypto/subtle"
+	"errors"
+	"math"
 	"net/http"
+	"path"
+	"strconv"
+	"time"
 
 	"be-job-portal/internal/delivery/http/dto"
 	"be-job-portal/internal/domain"
 	"be-job-portal/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -45,27 +52,479 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authUsecase.Login(c.Request.Context(), input.Email, input.Password)
+	result, err := h.authUsecase.Login(c.Request.Context(), input.Email, input.Password, clientInfo(c))
 	if err != nil {
+		if errors.Is(err, domain.ErrTooManyRequests) {
+			respondTooManyAttempts(c, "Login failed", err)
+			return
+		}
+		if err == domain.ErrAccountSuspended {
+			respondSuspended(c, "Login failed")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Login failed", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Login successful", gin.H{"token": token})
+	respondLogin(c, "Login successful", result)
 }
 
-func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+// oauthStateCookie ties the state parameter to the browser that started the login,
+// so a callback link crafted by someone else is rejected (login CSRF).
+const oauthStateCookie = "oauth_state"
+
+func (h *AuthHandler) OAuthLogin(c *gin.Context) {
+	redirect, err := h.authUsecase.OAuthLoginURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Provider not found", "Login provider is not configured")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to start login", err.Error())
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, redirect.State, int(time.Until(redirect.ExpiresAt).Seconds()), providerCookiePath(c), "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, redirect.URL)
+}
+
+func (h *AuthHandler) OAuthCallback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Login cancelled", providerErr)
+		return
+	}
+
 	code := c.Query("code")
 	if code == "" {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Code not found", "Authorization code is missing")
 		return
 	}
+	state := c.Query("state")
+	if state == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "State not found", "State parameter is missing")
+		return
+	}
+
+	cookieState, _ := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, providerCookiePath(c), "", c.Request.TLS != nil, true)
+	if subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) != 1 {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Login failed", "Login was not started from this browser, please start again")
+		return
+	}
+
+	result, err := h.authUsecase.OAuthLogin(c.Request.Context(), c.Param("provider"), code, state, clientInfo(c))
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Provider not found", "Login provider is not configured")
+		case domain.ErrUnauthorized:
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Login failed", "Login request is invalid or has expired, please start again")
+		case domain.ErrConflict:
+			utils.ErrorResponse(c, http.StatusConflict, "Login failed", "This login belongs to another account. Sign in to that account and link the provider from your settings")
+		case domain.ErrAccountSuspended:
+			respondSuspended(c, "Login failed")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Login failed", err.Error())
+		}
+		return
+	}
+
+	respondLogin(c, "Login successful", result)
+}
+
+// providerCookiePath scopes the state cookie to /api/auth/<provider>, which covers
+// both the login and callback routes.
+func providerCookiePath(c *gin.Context) string {
+	return path.Dir(c.Request.URL.Path)
+}
+
+// respondLogin returns the token pair, or the challenge the client must answer at /2fa/verify.
+func respondLogin(c *gin.Context, message string, result *domain.LoginResult) {
+	if result.LinkedIdentity != nil {
+		utils.SuccessResponse(c, http.StatusOK, "Account linked successfully", result.LinkedIdentity)
+		return
+	}
+	if result.ChallengeToken != "" {
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", gin.H{
+			"two_factor_required": true,
+			"challenge_token":     result.ChallengeToken,
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, message, result.Tokens)
+}
+
+func clientInfo(c *gin.Context) domain.ClientInfo {
+	return domain.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// respondTooManyAttempts answers 429 and tells the client when it may retry.
+func respondTooManyAttempts(c *gin.Context, message string, err error) {
+	var retryErr *domain.RetryAfterError
+	if errors.As(err, &retryErr) {
+		seconds := int(math.Ceil(retryErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+	}
+	utils.ErrorResponse(c, http.StatusTooManyRequests, message, "Too many failed attempts, please try again later")
+}
+
+func respondSuspended(c *gin.Context, message string) {
+	utils.ErrorResponse(c, http.StatusForbidden, message, "This account has been suspended")
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var input dto.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	tokens, err := h.authUsecase.Refresh(c.Request.Context(), input.RefreshToken)
+	if err != nil {
+		if err == domain.ErrUnauthorized {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Refresh failed", "Refresh token is invalid, expired or revoked")
+			return
+		}
+		if err == domain.ErrAccountSuspended {
+			respondSuspended(c, "Refresh failed")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Refresh failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+	sessionID, err := utils.GetSessionID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Session ID not found in context")
+		return
+	}
+
+	if err := h.authUsecase.Logout(c.Request.Context(), userID, sessionID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Logout failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logout successful", nil)
+}
+
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var input dto.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	if err := h.authUsecase.ForgotPassword(c.Request.Context(), input.Email); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to process request", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If an account exists for this email, a password reset link has been sent", nil)
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var input dto.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	err := h.authUsecase.ResetPassword(c.Request.Context(), input.Token, input.Password)
+	if err != nil {
+		if err == domain.ErrBadRequest {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Password reset failed", "Reset token is invalid or has expired")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Password reset failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password has been reset successfully", nil)
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var input dto.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	err := h.authUsecase.VerifyEmail(c.Request.Context(), input.Token)
+	if err != nil {
+		if err == domain.ErrBadRequest {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Email verification failed", "Verification token is invalid or has expired")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Email verification failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", nil)
+}
+
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	err = h.authUsecase.ResendVerification(c.Request.Context(), userID)
+	if err != nil {
+		switch err {
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Resend failed", "Email address is already verified")
+		case domain.ErrTooManyRequests:
+			utils.ErrorResponse(c, http.StatusTooManyRequests, "Resend failed", "Please wait before requesting another verification email")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Resend failed", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verification email sent", nil)
+}
+
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var input dto.VerifyTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	tokens, err := h.authUsecase.VerifyTwoFactor(c.Request.Context(), input.ChallengeToken, input.Code, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrTooManyRequests) {
+			respondTooManyAttempts(c, "Two-factor verification failed", err)
+			return
+		}
+		if err == domain.ErrUnauthorized {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Two-factor verification failed", "Challenge or code is invalid")
+			return
+		}
+		if err == domain.ErrAccountSuspended {
+			respondSuspended(c, "Two-factor verification failed")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Two-factor verification failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", tokens)
+}
+
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	enrollment, err := h.authUsecase.EnrollTwoFactor(c.Request.Context(), userID)
+	if err != nil {
+		if err == domain.ErrBadRequest {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Enrollment failed", "Two-factor authentication is already enabled")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Enrollment failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the provisioning URI with your authenticator app, then confirm with a code", enrollment)
+}
+
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	var input dto.TwoFactorCodeRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	codes, err := h.authUsecase.ConfirmTwoFactor(c.Request.Context(), userID, input.Code)
+	if err != nil {
+		switch err {
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Confirmation failed", "No pending two-factor enrollment")
+		case domain.ErrUnauthorized:
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Confirmation failed", "Code is invalid")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Confirmation failed", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled. Store these recovery codes somewhere safe", gin.H{"recovery_codes": codes})
+}
+
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var input dto.TwoFactorCodeRequest
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	err = h.authUsecase.DisableTwoFactor(c.Request.Context(), userID, input.Code)
+	if err != nil {
+		if errors.Is(err, domain.ErrTooManyRequests) {
+			respondTooManyAttempts(c, "Disable failed", err)
+			return
+		}
+		switch err {
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Disable failed", "Two-factor authentication is not enabled")
+		case domain.ErrUnauthorized:
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Disable failed", "Code is invalid")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Disable failed", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+func (h *AuthHandler) StartIdentityLink(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	redirect, err := h.authUsecase.StartIdentityLink(c.Request.Context(), userID, c.Param("provider"))
+	if err != nil {
+		if err == domain.ErrNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Provider not found", "Login provider is not configured")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadGateway, "Failed to start linking", err.Error())
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, redirect.State, int(time.Until(redirect.ExpiresAt).Seconds()), providerCookiePath(c), "", c.Request.TLS != nil, true)
+	utils.SuccessResponse(c, http.StatusOK, "Open the URL in this browser to link the account", gin.H{"url": redirect.URL})
+}
+
+func (h *AuthHandler) ListIdentities(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	identities, err := h.authUsecase.ListIdentities(c.Request.Context(), userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch identities", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Identities fetched successfully", identities)
+}
+
+func (h *AuthHandler) UnlinkIdentity(c *gin.Context) {
+	identityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid identity ID", err.Error())
+		return
+	}
+
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	err = h.authUsecase.UnlinkIdentity(c.Request.Context(), userID, identityID)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Identity not found", "Identity with given ID does not exist")
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Unlink failed", "Set a password or link another provider before removing your last sign-in method")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Unlink failed", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Identity unlinked successfully", nil)
+}
+
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+	sessionID, err := utils.GetSessionID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "Session ID not found in context")
+		return
+	}
+
+	sessions, err := h.authUsecase.ListSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch sessions", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sessions fetched successfully", sessions)
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid session ID", err.Error())
+		return
+	}
+
+	userID, err := utils.GetUserID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
 
-	token, err := h.authUsecase.GoogleLogin(c.Request.Context(), code)
+	err = h.authUsecase.RevokeSession(c.Request.Context(), userID, sessionID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Google login failed", err.Error())
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Session not found", "Session with given ID does not exist or has already ended")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke session", err.Error())
+		}
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Google login successful", gin.H{"token": token})
+	utils.SuccessResponse(c, http.StatusOK, "Session revoked successfully", nil)
 }
@@ -19,20 +19,18 @@ func NewDashboardHandler(appUsecase domain.ApplicationUsecase) *DashboardHandler
 }
 
 func (h *DashboardHandler) GetRecruiterStats(c *gin.Context) {
-	userID, err := utils.GetUserID(c)
+	actor, err := utils.GetActor(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
 
-	role, exists := c.Get("role")
-	if !exists || role.(string) != "RECRUITER" {
-		utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only recruiters can access dashboard")
-		return
-	}
-
-	stats, err := h.appUsecase.GetDashboardStats(c.Request.Context(), userID)
+	stats, err := h.appUsecase.GetDashboardStats(c.Request.Context(), actor)
 	if err != nil {
+		if err == domain.ErrForbidden {
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only recruiters can access dashboard")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch dashboard stats", err.Error())
 		return
 	}
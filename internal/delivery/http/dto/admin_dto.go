@@ -0,0 +1,5 @@
+package dto
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=SEEKER RECRUITER ADMIN"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=jobs:read jobs:write applications:read applications:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
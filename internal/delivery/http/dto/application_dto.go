@@ -7,11 +7,21 @@ import (
 )
 
 type ApplyJobRequest struct {
-	JobID        string `json:"job_id" binding:"required"`
-	ResumeURL    string `json:"resume_url" binding:"required"`
-	CoverLetter  string `json:"cover_letter"`
-	LinkedInURL  string `json:"linkedin_url"`
-	PortfolioURL string `json:"portfolio_url"`
+	JobID        string            `json:"job_id" binding:"required"`
+	ResumeURL    string            `json:"resume_url" binding:"required"`
+	CoverLetter  string            `json:"cover_letter"`
+	LinkedInURL  string            `json:"linkedin_url"`
+	PortfolioURL string            `json:"portfolio_url"`
+	Answers      []ScreeningAnswer `json:"answers" binding:"omitempty,max=20,dive"`
+}
+
+// ScreeningAnswer sets boolean, choices, number or text to match the question type.
+type ScreeningAnswer struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Boolean    *bool     `json:"boolean"`
+	Choices    []string  `json:"choices"`
+	Number     *float64  `json:"number"`
+	Text       string    `json:"text" binding:"max=2000"`
 }
 
 type UpdateApplicationStatusRequest struct {
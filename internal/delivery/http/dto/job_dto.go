@@ -1,19 +1,75 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type CreateJobRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Category    string   `json:"category"`
-	JobType     string   `json:"job_type"`
-	Salary      string   `json:"salary"`
-	Benefits    []string `json:"benefits"`
+	Title               string              `json:"title" binding:"required"`
+	Description         string              `json:"description" binding:"required"`
+	Category            string              `json:"category"`
+	JobType             string              `json:"job_type"`
+	Salary              Salary              `json:"salary"`
+	Benefits            []string            `json:"benefits"`
+	Questions           []ScreeningQuestion `json:"questions" binding:"omitempty,max=20,dive"`
+	RequiredSkills      []string            `json:"required_skills" binding:"omitempty,max=30,dive,required,max=50"`
+	PreferredSkills     []string            `json:"preferred_skills" binding:"omitempty,max=30,dive,required,max=50"`
+	MinExperienceYears  int                 `json:"min_experience_years" binding:"min=0,max=50"`
+	MinEducation        string              `json:"min_education" binding:"omitempty,oneof=high_school diploma bachelor master doctorate"`
+	ExpiresAt           *time.Time          `json:"expires_at"`
+	ApplicationDeadline *time.Time          `json:"application_deadline"`
 }
 
 type UpdateJobRequest struct {
-	Title       string   `json:"title" binding:"required"`
-	Description string   `json:"description" binding:"required"`
-	Category    string   `json:"category"`
-	JobType     string   `json:"job_type"`
-	Salary      string   `json:"salary"`
-	Benefits    []string `json:"benefits"`
+	Title               string              `json:"title" binding:"required"`
+	Description         string              `json:"description" binding:"required"`
+	Category            string              `json:"category"`
+	JobType             string              `json:"job_type"`
+	Salary              Salary              `json:"salary"`
+	Benefits            []string            `json:"benefits"`
+	Questions           []ScreeningQuestion `json:"questions" binding:"omitempty,max=20,dive"`
+	RequiredSkills      []string            `json:"required_skills" binding:"omitempty,max=30,dive,required,max=50"`
+	PreferredSkills     []string            `json:"preferred_skills" binding:"omitempty,max=30,dive,required,max=50"`
+	MinExperienceYears  int                 `json:"min_experience_years" binding:"min=0,max=50"`
+	MinEducation        string              `json:"min_education" binding:"omitempty,oneof=high_school diploma bachelor master doctorate"`
+	ExpiresAt           *time.Time          `json:"expires_at"`
+	ApplicationDeadline *time.Time          `json:"application_deadline"`
+}
+
+type ChangeJobStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=draft published paused closed archived"`
+}
+
+// Salary is both-or-neither on min and max; a fixed amount has min equal to max.
+// Currency is an ISO 4217 code such as IDR or USD.
+type Salary struct {
+	Min        *int64 `json:"min" binding:"required_with=Max,omitempty,min=0"`
+	Max        *int64 `json:"max" binding:"required_with=Min,omitempty,min=0,gtefield=Min"`
+	Currency   string `json:"currency" binding:"required_with=Min Max,omitempty,iso4217"`
+	Period     string `json:"period" binding:"required_with=Min Max,omitempty,oneof=hour month year"`
+	Negotiable bool   `json:"negotiable"`
+}
+
+// ScreeningQuestion leaves ID empty for a new question and repeats it when editing an
+// existing one. Options are only for the choice types.
+type ScreeningQuestion struct {
+	ID       uuid.UUID     `json:"id"`
+	Type     string        `json:"type" binding:"required,oneof=yes_no single_choice multiple_choice number text"`
+	Prompt   string        `json:"prompt" binding:"required,max=500"`
+	Required bool          `json:"required"`
+	Options  []string      `json:"options" binding:"omitempty,max=50,dive,required,max=200"`
+	Knockout *KnockoutRule `json:"knockout"`
+}
+
+// KnockoutRule uses equals for yes/no, one_of for single choice, all_of for multiple
+// choice and min and/or max for number questions.
+type KnockoutRule struct {
+	Action string   `json:"action" binding:"required,oneof=reject flag"`
+	Equals *bool    `json:"equals"`
+	OneOf  []string `json:"one_of"`
+	AllOf  []string `json:"all_of"`
+	Min    *float64 `json:"min"`
+	Max    *float64 `json:"max"`
 }
@@ -0,0 +1,18 @@
+package dto
+
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=200"`
+}
+
+type ChangeMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=OWNER ADMIN RECRUITER VIEWER"`
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=OWNER ADMIN RECRUITER VIEWER"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"be-job-portal/internal/delivery/http/dto"
 	"be-job-portal/internal/domain"
@@ -30,28 +32,179 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 		return
 	}
 
-	userID, err := utils.GetUserID(c)
+	actor, err := utils.GetActor(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
 
-	role, exists := c.Get("role")
-	if !exists || role.(string) != "RECRUITER" {
-		utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only recruiters can create jobs")
+	err = h.jobUsecase.CreateJob(c.Request.Context(), actor, &domain.Job{
+		Title:               input.Title,
+		Description:         input.Description,
+		Category:            input.Category,
+		JobType:             input.JobType,
+		Salary:              jobSalary(input.Salary),
+		Benefits:            input.Benefits,
+		Questions:           screeningQuestions(input.Questions),
+		RequiredSkills:      input.RequiredSkills,
+		PreferredSkills:     input.PreferredSkills,
+		MinExperienceYears:  input.MinExperienceYears,
+		MinEducation:        input.MinEducation,
+		ExpiresAt:           input.ExpiresAt,
+		ApplicationDeadline: input.ApplicationDeadline,
+	})
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		switch err {
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only recruiters can create jobs")
+		case domain.ErrEmailNotVerified:
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Please verify your email address before posting jobs")
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", jobDatesMessage)
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create job", err.Error())
+		}
 		return
 	}
 
-	err = h.jobUsecase.CreateJob(c.Request.Context(), input.Title, input.Description, input.Category, input.JobType, input.Salary, input.Benefits, userID)
+	utils.SuccessResponse(c, http.StatusCreated, "Job created successfully", nil)
+}
+
+// jobDatesMessage explains the ErrBadRequest returned when creating or editing a job.
+const jobDatesMessage = "expires_at and application_deadline must be in the future, and the deadline may not be after expires_at"
+
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	filter, ok := jobFilter(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.jobUsecase.ListJobs(c.Request.Context(), filter)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create job", err.Error())
+		if err == domain.ErrBadRequest {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid filter", "sort must be relevance, newest, salary_desc or salary_asc")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch jobs", err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusCreated, "Job created successfully", nil)
+	utils.SuccessResponse(c, http.StatusOK, "Jobs fetched successfully", result)
 }
 
-func (h *JobHandler) ListJobs(c *gin.Context) {
+// maxSearchQueryLength keeps search terms to a sensible size.
+const maxSearchQueryLength = 200
+
+func (h *JobHandler) SearchJobs(c *gin.Context) {
+	filter, ok := jobFilter(c)
+	if !ok {
+		return
+	}
+	if filter.Query == "" || len(filter.Query) > maxSearchQueryLength {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid search", "q is required and must be at most 200 characters")
+		return
+	}
+
+	result, err := h.jobUsecase.SearchJobs(c.Request.Context(), filter)
+	if err != nil {
+		if err == domain.ErrBadRequest {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid filter", "sort must be relevance, newest, salary_desc or salary_asc")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search jobs", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Jobs searched successfully", result)
+}
+
+// jobFilter reads the listing filters from the query string, answering 400 itself when
+// one is malformed.
+func jobFilter(c *gin.Context) (domain.JobFilter, bool) {
+	filter := domain.JobFilter{
+		Query:            strings.TrimSpace(c.Query("q")),
+		Category:         c.Query("category"),
+		JobType:          c.Query("job_type"),
+		Location:         strings.TrimSpace(c.Query("location")),
+		Currency:         strings.ToUpper(c.Query("currency")),
+		Sort:             c.Query("sort"),
+		PaginationParams: paginationParams(c),
+	}
+
+	if recruiterIDStr := c.Query("recruiter_id"); recruiterIDStr != "" {
+		recruiterID, err := uuid.Parse(recruiterIDStr)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recruiter ID", err.Error())
+			return filter, false
+		}
+		filter.RecruiterID = &recruiterID
+	}
+	for param, dst := range map[string]**int64{"min_salary": &filter.MinSalary, "max_salary": &filter.MaxSalary} {
+		if value := c.Query(param); value != "" {
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || amount < 0 {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Invalid filter", param+" must be a non-negative whole number")
+				return filter, false
+			}
+			*dst = &amount
+		}
+	}
+	return filter, true
+}
+
+func jobSalary(input dto.Salary) domain.Salary {
+	return domain.Salary{
+		Min:        input.Min,
+		Max:        input.Max,
+		Currency:   input.Currency,
+		Period:     input.Period,
+		Negotiable: input.Negotiable,
+	}
+}
+
+func screeningQuestions(input []dto.ScreeningQuestion) []domain.ScreeningQuestion {
+	if input == nil {
+		return nil
+	}
+	questions := make([]domain.ScreeningQuestion, len(input))
+	for i, q := range input {
+		questions[i] = domain.ScreeningQuestion{
+			ID:       q.ID,
+			Type:     q.Type,
+			Prompt:   q.Prompt,
+			Required: q.Required,
+			Options:  q.Options,
+		}
+		if q.Knockout != nil {
+			questions[i].Knockout = &domain.KnockoutRule{
+				Action: q.Knockout.Action,
+				Equals: q.Knockout.Equals,
+				OneOf:  q.Knockout.OneOf,
+				AllOf:  q.Knockout.AllOf,
+				Min:    q.Knockout.Min,
+				Max:    q.Knockout.Max,
+			}
+		}
+	}
+	return questions
+}
+
+// respondValidationError answers 400 with the detail of a domain.ValidationError and
+// reports whether err was one.
+func respondValidationError(c *gin.Context, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", validationErr.Message)
+	return true
+}
+
+// paginationParams reads page and limit, defaulting to the first 20 and capping at 100.
+func paginationParams(c *gin.Context) domain.PaginationParams {
 	page := 1
 	limit := 20
 
@@ -70,18 +223,7 @@ func (h *JobHandler) ListJobs(c *gin.Context) {
 		}
 	}
 
-	params := domain.PaginationParams{
-		Page:  page,
-		Limit: limit,
-	}
-
-	result, err := h.jobUsecase.ListJobs(c.Request.Context(), params)
-	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch jobs", err.Error())
-		return
-	}
-
-	utils.SuccessResponse(c, http.StatusOK, "Jobs fetched successfully", result)
+	return domain.PaginationParams{Page: page, Limit: limit}
 }
 
 func (h *JobHandler) GetJob(c *gin.Context) {
@@ -92,13 +234,19 @@ func (h *JobHandler) GetJob(c *gin.Context) {
 		return
 	}
 
-	job, err := h.jobUsecase.GetJob(c.Request.Context(), id)
+	actor, err := utils.GetActor(c)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch job", err.Error())
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
-	if job == nil {
-		utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+
+	job, err := h.jobUsecase.GetJob(c.Request.Context(), actor, id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch job", err.Error())
 		return
 	}
 
@@ -106,28 +254,30 @@ func (h *JobHandler) GetJob(c *gin.Context) {
 }
 
 func (h *JobHandler) ListJobsByRecruiter(c *gin.Context) {
-	// Get recruiter_id from query parameter (optional)
-	recruiterIDStr := c.Query("recruiter_id")
-
-	var recruiterID uuid.UUID
-	var err error
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
 
-	if recruiterIDStr != "" {
-		recruiterID, err = uuid.Parse(recruiterIDStr)
-		if err != nil {
-			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recruiter ID", err.Error())
+	// Without recruiter_id this lists every job of the caller's organization; only
+	// admins may look up another recruiter's postings.
+	var jobs []domain.Job
+	if recruiterIDStr := c.Query("recruiter_id"); recruiterIDStr != "" {
+		recruiterID, parseErr := uuid.Parse(recruiterIDStr)
+		if parseErr != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recruiter ID", parseErr.Error())
 			return
 		}
+		jobs, err = h.jobUsecase.ListJobsByRecruiter(c.Request.Context(), actor, recruiterID)
 	} else {
-		recruiterID, err = utils.GetUserID(c)
-		if err != nil {
-			utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
-			return
-		}
+		jobs, err = h.jobUsecase.ListOrganizationJobs(c.Request.Context(), actor)
 	}
-
-	jobs, err := h.jobUsecase.ListJobsByRecruiter(c.Request.Context(), recruiterID)
 	if err != nil {
+		if err == domain.ErrForbidden {
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "You can only list your own jobs")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch jobs", err.Error())
 		return
 	}
@@ -149,19 +299,40 @@ func (h *JobHandler) UpdateJob(c *gin.Context) {
 		return
 	}
 
-	userID, err := utils.GetUserID(c)
+	actor, err := utils.GetActor(c)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
 		return
 	}
 
-	err = h.jobUsecase.UpdateJob(c.Request.Context(), id, userID, input.Title, input.Description, input.Category, input.JobType, input.Salary, input.Benefits)
+	err = h.jobUsecase.UpdateJob(c.Request.Context(), actor, id, &domain.Job{
+		Title:               input.Title,
+		Description:         input.Description,
+		Category:            input.Category,
+		JobType:             input.JobType,
+		Salary:              jobSalary(input.Salary),
+		Benefits:            input.Benefits,
+		Questions:           screeningQuestions(input.Questions),
+		RequiredSkills:      input.RequiredSkills,
+		PreferredSkills:     input.PreferredSkills,
+		MinExperienceYears:  input.MinExperienceYears,
+		MinEducation:        input.MinEducation,
+		ExpiresAt:           input.ExpiresAt,
+		ApplicationDeadline: input.ApplicationDeadline,
+	})
 	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
 		switch err {
 		case domain.ErrNotFound:
 			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
-		case domain.ErrUnauthorized:
+		case domain.ErrForbidden:
 			utils.ErrorResponse(c, http.StatusForbidden, "Unauthorized", "You are not authorized to update this job")
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", jobDatesMessage)
+		case domain.ErrConflict:
+			utils.ErrorResponse(c, http.StatusConflict, "Job archived", "Archived jobs can no longer be edited")
 		default:
 			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update job", err.Error())
 		}
@@ -170,3 +341,160 @@ func (h *JobHandler) UpdateJob(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Job updated successfully", nil)
 }
+
+func (h *JobHandler) ChangeJobStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID", err.Error())
+		return
+	}
+
+	var input dto.ChangeJobStatusRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	job, err := h.jobUsecase.ChangeJobStatus(c.Request.Context(), actor, id, input.Status)
+	if err != nil {
+		switch err {
+		case domain.ErrBadRequest:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid status", "status must be draft, published, paused, closed or archived")
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Unauthorized", "You are not authorized to update this job")
+		case domain.ErrConflict:
+			utils.ErrorResponse(c, http.StatusConflict, "Invalid transition", "The job cannot move from its current status to "+input.Status)
+		case domain.ErrJobExpired:
+			utils.ErrorResponse(c, http.StatusConflict, "Job expired", "Move expires_at into the future before publishing the job again")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to change job status", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Job status changed successfully", job)
+}
+
+func (h *JobHandler) DeleteJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	if err := h.jobUsecase.DeleteJob(c.Request.Context(), actor, id); err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Unauthorized", "You are not authorized to delete this job")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete job", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Job deleted successfully", nil)
+}
+
+func (h *JobHandler) RestoreJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	job, err := h.jobUsecase.RestoreJob(c.Request.Context(), actor, id)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Unauthorized", "You are not authorized to restore this job")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to restore job", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Job restored successfully", job)
+}
+
+func (h *JobHandler) PurgeJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	if err := h.jobUsecase.PurgeJob(c.Request.Context(), actor, id); err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only admins can purge jobs")
+		case domain.ErrConflict:
+			utils.ErrorResponse(c, http.StatusConflict, "Job has applications", "Jobs with applications keep the candidate history and can only be deleted, not purged")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to purge job", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Job purged successfully", nil)
+}
+
+func (h *JobHandler) ListRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid job ID", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	revisions, err := h.jobUsecase.ListRevisions(c.Request.Context(), actor, id)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			utils.ErrorResponse(c, http.StatusNotFound, "Job not found", "Job with given ID does not exist")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only the organization that owns the job can see its history")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch job revisions", err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Job revisions fetched successfully", revisions)
+}
@@ -0,0 +1,248 @@
+package http
+
+import (
+	"be-job-portal/internal/delivery/http/dto"
+	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrganizationHandler struct {
+	orgUsecase domain.OrganizationUsecase
+}
+
+func NewOrganizationHandler(orgUsecase domain.OrganizationUsecase) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgUsecase: orgUsecase,
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var input dto.OrganizationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	member, err := h.orgUsecase.CreateOrganization(c.Request.Context(), actor, input.Name)
+	if err != nil {
+		respondOrganizationError(c, "Failed to create organization", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Organization created successfully", member)
+}
+
+func (h *OrganizationHandler) GetMyOrganization(c *gin.Context) {
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	member, err := h.orgUsecase.GetMyOrganization(c.Request.Context(), actor)
+	if err != nil {
+		respondOrganizationError(c, "Failed to fetch organization", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Organization fetched successfully", member)
+}
+
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var input dto.OrganizationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	org, err := h.orgUsecase.UpdateOrganization(c.Request.Context(), actor, input.Name)
+	if err != nil {
+		respondOrganizationError(c, "Failed to update organization", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Organization updated successfully", org)
+}
+
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	members, err := h.orgUsecase.ListMembers(c.Request.Context(), actor)
+	if err != nil {
+		respondOrganizationError(c, "Failed to fetch members", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Members fetched successfully", members)
+}
+
+func (h *OrganizationHandler) ChangeMemberRole(c *gin.Context) {
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid member ID", err.Error())
+		return
+	}
+
+	var input dto.ChangeMemberRoleRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	if err := h.orgUsecase.ChangeMemberRole(c.Request.Context(), actor, memberID, input.Role); err != nil {
+		respondOrganizationError(c, "Failed to change member role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Member role changed successfully", nil)
+}
+
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	memberID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid member ID", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	if err := h.orgUsecase.RemoveMember(c.Request.Context(), actor, memberID); err != nil {
+		respondOrganizationError(c, "Failed to remove member", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Member removed successfully", nil)
+}
+
+func (h *OrganizationHandler) Invite(c *gin.Context) {
+	var input dto.InviteMemberRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	invitation, err := h.orgUsecase.Invite(c.Request.Context(), actor, input.Email, input.Role)
+	if err != nil {
+		respondOrganizationError(c, "Failed to send invitation", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Invitation sent successfully", invitation)
+}
+
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	invitations, err := h.orgUsecase.ListInvitations(c.Request.Context(), actor)
+	if err != nil {
+		respondOrganizationError(c, "Failed to fetch invitations", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitations fetched successfully", invitations)
+}
+
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	if err := h.orgUsecase.RevokeInvitation(c.Request.Context(), actor, invitationID); err != nil {
+		respondOrganizationError(c, "Failed to revoke invitation", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation revoked successfully", nil)
+}
+
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	var input dto.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
+		return
+	}
+
+	actor, err := utils.GetActor(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized", "User ID not found in context")
+		return
+	}
+
+	member, err := h.orgUsecase.AcceptInvitation(c.Request.Context(), actor, input.Token)
+	if err != nil {
+		switch err {
+		case domain.ErrUnauthorized:
+			utils.ErrorResponse(c, http.StatusBadRequest, "Failed to accept invitation", "Invitation is invalid, expired or already used")
+		case domain.ErrForbidden:
+			utils.ErrorResponse(c, http.StatusForbidden, "Failed to accept invitation", "Sign in with the recruiter account the invitation was sent to")
+		default:
+			respondOrganizationError(c, "Failed to accept invitation", err)
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation accepted successfully", member)
+}
+
+func respondOrganizationError(c *gin.Context, message string, err error) {
+	switch err {
+	case domain.ErrNotFound:
+		utils.ErrorResponse(c, http.StatusNotFound, message, "Organization, member or invitation not found")
+	case domain.ErrForbidden:
+		utils.ErrorResponse(c, http.StatusForbidden, message, "Your organization role does not allow this")
+	case domain.ErrConflict:
+		utils.ErrorResponse(c, http.StatusConflict, message, "You already belong to an organization; leave it first")
+	case domain.ErrBadRequest:
+		utils.ErrorResponse(c, http.StatusBadRequest, message, "An organization must keep at least one owner")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, message, err.Error())
+	}
+}
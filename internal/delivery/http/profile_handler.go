@@ -38,7 +38,7 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	profile, err := h.profileUsecase.GetProfile(c.Request.Context(), userID, user.Role)
+	profile, err := h.profileUsecase.GetProfile(c.Request.Context(), domain.Actor{UserID: userID, Role: user.Role})
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch profile", err.Error())
 		return
@@ -61,7 +61,7 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	switch strings.ToUpper(user.Role) {
-	case "SEEKER":
+	case domain.RoleSeeker:
 		var input dto.UpdateSeekerProfileRequest
 		if err := c.ShouldBindJSON(&input); err != nil {
 			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
@@ -117,7 +117,7 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 		}
 		err = h.profileUsecase.UpdateSeekerProfile(c.Request.Context(), userID, profile)
 
-	case "RECRUITER":
+	case domain.RoleRecruiter:
 		var input dto.UpdateCompanyProfileRequest
 		if err := c.ShouldBindJSON(&input); err != nil {
 			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid input", err.Error())
@@ -132,7 +132,7 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 			Description: input.Description,
 			LogoURL:     input.LogoURL,
 		}
-		err = h.profileUsecase.UpdateCompanyProfile(c.Request.Context(), userID, profile)
+		err = h.profileUsecase.UpdateCompanyProfile(c.Request.Context(), domain.Actor{UserID: userID, Role: user.Role}, profile)
 
 	default:
 		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid role", "User role is not recognized")
@@ -140,6 +140,10 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	if err != nil {
+		if err == domain.ErrForbidden {
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "Only organization owners and admins can edit the company profile")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update profile", err.Error())
 		return
 	}
@@ -1,44 +1,82 @@
 package http
 
 import (
+	"be-job-portal/internal/domain"
 	"be-job-portal/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(r *gin.Engine, authHandler *AuthHandler, jobHandler *JobHandler, appHandler *ApplicationHandler, profileHandler *ProfileHandler, dashboardHandler *DashboardHandler) {
+func RegisterRoutes(r *gin.Engine, authMiddleware gin.HandlerFunc, authHandler *AuthHandler, jobHandler *JobHandler, appHandler *ApplicationHandler, profileHandler *ProfileHandler, dashboardHandler *DashboardHandler, orgHandler *OrganizationHandler, apiKeyHandler *APIKeyHandler, adminHandler *AdminHandler, jwksHandler *JWKSHandler) {
+	seekerOnly := utils.RequireRole(domain.RoleSeeker)
+	recruiterOnly := utils.RequireRole(domain.RoleRecruiter)
+	recruiterOrAdmin := utils.RequireRole(domain.RoleRecruiter, domain.RoleAdmin)
+	adminOnly := utils.RequireRole(domain.RoleAdmin)
+	// API keys only reach routes that declare a scope; everything else needs a signed-in user.
+	requireSession := utils.RequireSession()
+	// Admins impersonating a user cannot change that user's credentials or access.
+	denyImpersonation := utils.DenyImpersonation()
+	jobsRead := utils.RequireScope(domain.ScopeJobsRead)
+	jobsWrite := utils.RequireScope(domain.ScopeJobsWrite)
+	applicationsRead := utils.RequireScope(domain.ScopeApplicationsRead)
+	applicationsWrite := utils.RequireScope(domain.ScopeApplicationsWrite)
+
+	// Public keys for verifying access tokens
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	// Auth Routes
 	auth := r.Group("/api/auth")
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
-		auth.GET("/google/callback", authHandler.GoogleCallback)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.POST("/logout", authMiddleware, requireSession, authHandler.Logout)
+		auth.GET("/sessions", authMiddleware, requireSession, authHandler.ListSessions)
+		auth.DELETE("/sessions/:id", authMiddleware, requireSession, denyImpersonation, authHandler.RevokeSession)
+		auth.POST("/password/forgot", authHandler.ForgotPassword)
+		auth.POST("/password/reset", authHandler.ResetPassword)
+		auth.POST("/email/verify", authHandler.VerifyEmail)
+		auth.POST("/email/resend", authMiddleware, requireSession, authHandler.ResendVerification)
+		auth.POST("/2fa/verify", authHandler.VerifyTwoFactor)
+		auth.POST("/2fa/enroll", authMiddleware, requireSession, denyImpersonation, authHandler.EnrollTwoFactor)
+		auth.POST("/2fa/confirm", authMiddleware, requireSession, denyImpersonation, authHandler.ConfirmTwoFactor)
+		auth.POST("/2fa/disable", authMiddleware, requireSession, denyImpersonation, authHandler.DisableTwoFactor)
+		auth.GET("/:provider/login", authHandler.OAuthLogin)
+		auth.GET("/:provider/callback", authHandler.OAuthCallback)
+		auth.POST("/:provider/link", authMiddleware, requireSession, denyImpersonation, authHandler.StartIdentityLink)
+		auth.GET("/identities", authMiddleware, requireSession, authHandler.ListIdentities)
+		auth.DELETE("/identities/:id", authMiddleware, requireSession, denyImpersonation, authHandler.UnlinkIdentity)
 	}
 
 	// Job Routes
 	jobs := r.Group("/api/jobs")
-	jobs.Use(utils.AuthMiddleware())
+	jobs.Use(authMiddleware)
 	{
-		jobs.POST("", jobHandler.CreateJob)
-		jobs.GET("", jobHandler.ListJobs)
-		jobs.GET("/recruiter", jobHandler.ListJobsByRecruiter)
-		jobs.GET("/:id", jobHandler.GetJob)
-		jobs.PUT("/:id", jobHandler.UpdateJob)
-		jobs.GET("/:id/applicants", appHandler.ListJobApplicants)
+		jobs.POST("", jobsWrite, recruiterOnly, jobHandler.CreateJob)
+		jobs.GET("", jobsRead, jobHandler.ListJobs)
+		jobs.GET("/search", jobsRead, jobHandler.SearchJobs)
+		jobs.GET("/recruiter", jobsRead, recruiterOrAdmin, jobHandler.ListJobsByRecruiter)
+		jobs.GET("/:id", jobsRead, jobHandler.GetJob)
+		jobs.PUT("/:id", jobsWrite, recruiterOrAdmin, jobHandler.UpdateJob)
+		jobs.PUT("/:id/status", jobsWrite, recruiterOrAdmin, jobHandler.ChangeJobStatus)
+		jobs.DELETE("/:id", jobsWrite, recruiterOrAdmin, jobHandler.DeleteJob)
+		jobs.POST("/:id/restore", jobsWrite, recruiterOrAdmin, jobHandler.RestoreJob)
+		jobs.GET("/:id/revisions", jobsRead, recruiterOrAdmin, jobHandler.ListRevisions)
+		jobs.GET("/:id/applicants", applicationsRead, recruiterOnly, appHandler.ListJobApplicants)
 	}
 
 	// Application Routes
 	apps := r.Group("/api/applications")
-	apps.Use(utils.AuthMiddleware())
+	apps.Use(authMiddleware)
 	{
-		apps.POST("", appHandler.ApplyJob)
-		apps.GET("", appHandler.ListApplications)
-		apps.PUT("/:id/status", appHandler.UpdateStatus)
+		apps.POST("", requireSession, seekerOnly, appHandler.ApplyJob)
+		apps.GET("", requireSession, seekerOnly, appHandler.ListApplications)
+		apps.PUT("/:id/status", applicationsWrite, recruiterOnly, appHandler.UpdateStatus)
 	}
 
 	// Profile Routes
 	profile := r.Group("/api/profile")
-	profile.Use(utils.AuthMiddleware())
+	profile.Use(authMiddleware, requireSession, utils.RequireRole(domain.RoleSeeker, domain.RoleRecruiter))
 	{
 		profile.GET("", profileHandler.GetProfile)
 		profile.PUT("", profileHandler.UpdateProfile)
@@ -46,8 +84,48 @@ func RegisterRoutes(r *gin.Engine, authHandler *AuthHandler, jobHandler *JobHand
 
 	// Dashboard Routes
 	dashboard := r.Group("/api/dashboard")
-	dashboard.Use(utils.AuthMiddleware())
+	dashboard.Use(authMiddleware, requireSession, recruiterOnly)
 	{
 		dashboard.GET("/stats", dashboardHandler.GetRecruiterStats)
 	}
+
+	// Organization Routes
+	orgs := r.Group("/api/organizations")
+	orgs.Use(authMiddleware, requireSession, recruiterOnly)
+	{
+		orgs.POST("", orgHandler.CreateOrganization)
+		orgs.GET("/me", orgHandler.GetMyOrganization)
+		orgs.PUT("/me", orgHandler.UpdateOrganization)
+		orgs.GET("/me/members", orgHandler.ListMembers)
+		orgs.PUT("/me/members/:id", denyImpersonation, orgHandler.ChangeMemberRole)
+		orgs.DELETE("/me/members/:id", denyImpersonation, orgHandler.RemoveMember)
+		orgs.GET("/me/invitations", orgHandler.ListInvitations)
+		orgs.POST("/me/invitations", denyImpersonation, orgHandler.Invite)
+		orgs.DELETE("/me/invitations/:id", orgHandler.RevokeInvitation)
+		orgs.POST("/invitations/accept", denyImpersonation, orgHandler.AcceptInvitation)
+	}
+
+	// API Key Routes
+	apiKeys := r.Group("/api/api-keys")
+	apiKeys.Use(authMiddleware, requireSession, denyImpersonation, recruiterOnly)
+	{
+		apiKeys.POST("", apiKeyHandler.CreateKey)
+		apiKeys.GET("", apiKeyHandler.ListKeys)
+		apiKeys.DELETE("/:id", apiKeyHandler.RevokeKey)
+	}
+
+	// Admin Routes
+	admin := r.Group("/api/admin")
+	admin.Use(authMiddleware, requireSession, adminOnly)
+	{
+		admin.GET("/users", adminHandler.ListUsers)
+		admin.GET("/users/:id", adminHandler.GetUser)
+		admin.PUT("/users/:id/role", adminHandler.ChangeRole)
+		admin.POST("/users/:id/suspend", adminHandler.SuspendUser)
+		admin.POST("/users/:id/unsuspend", adminHandler.UnsuspendUser)
+		admin.POST("/users/:id/logout", adminHandler.ForceLogout)
+		admin.POST("/users/:id/impersonate", adminHandler.Impersonate)
+		admin.GET("/audit-events", adminHandler.ListAuditEvents)
+		admin.DELETE("/jobs/:id", jobHandler.PurgeJob)
+	}
 }
@@ -0,0 +1,219 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// routeAccess describes who RegisterRoutes lets through to a route's handler.
+type routeAccess struct {
+	method string
+	path   string
+	// public routes skip authentication entirely.
+	public bool
+	roles  []string
+	// scope is what an API key needs; routes without one reject API keys.
+	scope           string
+	denyImpersonate bool
+}
+
+var anyRole = []string{domain.RoleSeeker, domain.RoleRecruiter, domain.RoleAdmin}
+
+var routeTable = []routeAccess{
+	{method: "GET", path: "/.well-known/jwks.json", public: true},
+
+	{method: "POST", path: "/api/auth/register", public: true},
+	{method: "POST", path: "/api/auth/login", public: true},
+	{method: "POST", path: "/api/auth/refresh", public: true},
+	{method: "POST", path: "/api/auth/logout", roles: anyRole},
+	{method: "GET", path: "/api/auth/sessions", roles: anyRole},
+	{method: "DELETE", path: "/api/auth/sessions/:id", roles: anyRole, denyImpersonate: true},
+	{method: "POST", path: "/api/auth/password/forgot", public: true},
+	{method: "POST", path: "/api/auth/password/reset", public: true},
+	{method: "POST", path: "/api/auth/email/verify", public: true},
+	{method: "POST", path: "/api/auth/email/resend", roles: anyRole},
+	{method: "POST", path: "/api/auth/2fa/verify", public: true},
+	{method: "POST", path: "/api/auth/2fa/enroll", roles: anyRole, denyImpersonate: true},
+	{method: "POST", path: "/api/auth/2fa/confirm", roles: anyRole, denyImpersonate: true},
+	{method: "POST", path: "/api/auth/2fa/disable", roles: anyRole, denyImpersonate: true},
+	{method: "GET", path: "/api/auth/google/login", public: true},
+	{method: "GET", path: "/api/auth/google/callback", public: true},
+	{method: "POST", path: "/api/auth/google/link", roles: anyRole, denyImpersonate: true},
+	{method: "GET", path: "/api/auth/identities", roles: anyRole},
+	{method: "DELETE", path: "/api/auth/identities/:id", roles: anyRole, denyImpersonate: true},
+
+	{method: "POST", path: "/api/jobs", roles: []string{domain.RoleRecruiter}, scope: domain.ScopeJobsWrite},
+	{method: "GET", path: "/api/jobs", roles: anyRole, scope: domain.ScopeJobsRead},
+	{method: "GET", path: "/api/jobs/search", roles: anyRole, scope: domain.ScopeJobsRead},
+	{method: "GET", path: "/api/jobs/recruiter", roles: []string{domain.RoleRecruiter, domain.RoleAdmin}, scope: domain.ScopeJobsRead},
+	{method: "GET", path: "/api/jobs/:id", roles: anyRole, scope: domain.ScopeJobsRead},
+	{method: "PUT", path: "/api/jobs/:id", roles: []string{domain.RoleRecruiter, domain.RoleAdmin}, scope: domain.ScopeJobsWrite},
+	{method: "PUT", path: "/api/jobs/:id/status", roles: []string{domain.RoleRecruiter, domain.RoleAdmin}, scope: domain.ScopeJobsWrite},
+	{method: "DELETE", path: "/api/jobs/:id", roles: []string{domain.RoleRecruiter, domain.RoleAdmin}, scope: domain.ScopeJobsWrite},
+	{method: "POST", path: "/api/jobs/:id/restore", roles: []string{domain.RoleRecruiter, domain.RoleAdmin}, scope: domain.ScopeJobsWrite},
+	{method: "GET", path: "/api/jobs/:id/revisions", roles: []string{domain.RoleRecruiter, domain.RoleAdmin}, scope: domain.ScopeJobsRead},
+	{method: "GET", path: "/api/jobs/:id/applicants", roles: []string{domain.RoleRecruiter}, scope: domain.ScopeApplicationsRead},
+
+	{method: "POST", path: "/api/applications", roles: []string{domain.RoleSeeker}},
+	{method: "GET", path: "/api/applications", roles: []string{domain.RoleSeeker}},
+	{method: "PUT", path: "/api/applications/:id/status", roles: []string{domain.RoleRecruiter}, scope: domain.ScopeApplicationsWrite},
+
+	{method: "GET", path: "/api/profile", roles: []string{domain.RoleSeeker, domain.RoleRecruiter}},
+	{method: "PUT", path: "/api/profile", roles: []string{domain.RoleSeeker, domain.RoleRecruiter}},
+
+	{method: "GET", path: "/api/dashboard/stats", roles: []string{domain.RoleRecruiter}},
+
+	{method: "POST", path: "/api/organizations", roles: []string{domain.RoleRecruiter}},
+	{method: "GET", path: "/api/organizations/me", roles: []string{domain.RoleRecruiter}},
+	{method: "PUT", path: "/api/organizations/me", roles: []string{domain.RoleRecruiter}},
+	{method: "GET", path: "/api/organizations/me/members", roles: []string{domain.RoleRecruiter}},
+	{method: "PUT", path: "/api/organizations/me/members/:id", roles: []string{domain.RoleRecruiter}, denyImpersonate: true},
+	{method: "DELETE", path: "/api/organizations/me/members/:id", roles: []string{domain.RoleRecruiter}, denyImpersonate: true},
+	{method: "GET", path: "/api/organizations/me/invitations", roles: []string{domain.RoleRecruiter}},
+	{method: "POST", path: "/api/organizations/me/invitations", roles: []string{domain.RoleRecruiter}, denyImpersonate: true},
+	{method: "DELETE", path: "/api/organizations/me/invitations/:id", roles: []string{domain.RoleRecruiter}},
+	{method: "POST", path: "/api/organizations/invitations/accept", roles: []string{domain.RoleRecruiter}, denyImpersonate: true},
+
+	{method: "POST", path: "/api/api-keys", roles: []string{domain.RoleRecruiter}, denyImpersonate: true},
+	{method: "GET", path: "/api/api-keys", roles: []string{domain.RoleRecruiter}, denyImpersonate: true},
+	{method: "DELETE", path: "/api/api-keys/:id", roles: []string{domain.RoleRecruiter}, denyImpersonate: true},
+
+	{method: "GET", path: "/api/admin/users", roles: []string{domain.RoleAdmin}},
+	{method: "GET", path: "/api/admin/users/:id", roles: []string{domain.RoleAdmin}},
+	{method: "PUT", path: "/api/admin/users/:id/role", roles: []string{domain.RoleAdmin}},
+	{method: "POST", path: "/api/admin/users/:id/suspend", roles: []string{domain.RoleAdmin}},
+	{method: "POST", path: "/api/admin/users/:id/unsuspend", roles: []string{domain.RoleAdmin}},
+	{method: "POST", path: "/api/admin/users/:id/logout", roles: []string{domain.RoleAdmin}},
+	{method: "POST", path: "/api/admin/users/:id/impersonate", roles: []string{domain.RoleAdmin}},
+	{method: "GET", path: "/api/admin/audit-events", roles: []string{domain.RoleAdmin}},
+	{method: "DELETE", path: "/api/admin/jobs/:id", roles: []string{domain.RoleAdmin}},
+}
+
+// caller is who a test request claims to be. The fake auth middleware trusts it as is.
+type caller struct {
+	name         string
+	role         string
+	apiKey       bool
+	scopes       []string
+	impersonated bool
+}
+
+// fakeAuth stands in for AuthMiddleware, setting the same context keys from the caller.
+func fakeAuth(who *caller) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if who.role == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set("user_id", uuid.New())
+		c.Set("role", who.role)
+		if who.apiKey {
+			c.Set("api_key_id", uuid.New())
+			c.Set("api_key_scopes", who.scopes)
+		} else {
+			c.Set("session_id", uuid.New())
+		}
+		if who.impersonated {
+			c.Set("impersonator_id", uuid.New())
+		}
+		c.Next()
+	}
+}
+
+// newTestRouter registers the real routes with handlers that have no usecases behind
+// them. A request that gets past the middleware therefore ends in the handler, which
+// either rejects the empty request or panics on the missing usecase; both are reported
+// as reachedHandler so they cannot be mistaken for an authorization failure.
+func newTestRouter(who *caller) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, _ interface{}) {
+		c.AbortWithStatus(reachedHandler)
+	}))
+	RegisterRoutes(r, fakeAuth(who), &AuthHandler{}, &JobHandler{}, &ApplicationHandler{}, &ProfileHandler{}, &DashboardHandler{}, &OrganizationHandler{}, &APIKeyHandler{}, &AdminHandler{}, &JWKSHandler{})
+	return r
+}
+
+const reachedHandler = http.StatusTeapot
+
+func allows(route routeAccess, who caller) bool {
+	if route.public {
+		return true
+	}
+	if who.role == "" {
+		return false
+	}
+	if who.apiKey && (route.scope == "" || !contains(who.scopes, route.scope)) {
+		return false
+	}
+	if who.impersonated && route.denyImpersonate {
+		return false
+	}
+	return contains(route.roles, who.role)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRegisterRoutesAccess(t *testing.T) {
+	allScopes := []string{domain.ScopeJobsRead, domain.ScopeJobsWrite, domain.ScopeApplicationsRead, domain.ScopeApplicationsWrite}
+	callers := []caller{
+		{name: "anonymous"},
+		{name: "seeker", role: domain.RoleSeeker},
+		{name: "recruiter", role: domain.RoleRecruiter},
+		{name: "admin", role: domain.RoleAdmin},
+		{name: "impersonated seeker", role: domain.RoleSeeker, impersonated: true},
+		{name: "impersonated recruiter", role: domain.RoleRecruiter, impersonated: true},
+		{name: "api key with every scope", role: domain.RoleRecruiter, apiKey: true, scopes: allScopes},
+		{name: "read-only api key", role: domain.RoleRecruiter, apiKey: true, scopes: []string{domain.ScopeJobsRead, domain.ScopeApplicationsRead}},
+		{name: "api key without scopes", role: domain.RoleRecruiter, apiKey: true, scopes: []string{}},
+	}
+
+	for _, who := range callers {
+		who := who
+		r := newTestRouter(&who)
+		for _, route := range routeTable {
+			path := strings.ReplaceAll(route.path, ":id", uuid.NewString())
+			t.Run(who.name+" "+route.method+" "+route.path, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(route.method, path, nil))
+
+				if w.Code == http.StatusNotFound || w.Code == http.StatusMethodNotAllowed {
+					t.Fatalf("route is not registered (status %d)", w.Code)
+				}
+				denied := w.Code == http.StatusUnauthorized || w.Code == http.StatusForbidden
+				if want := allows(route, who); denied == want {
+					t.Fatalf("status %d, want allowed = %v", w.Code, want)
+				}
+			})
+		}
+	}
+}
+
+func TestRegisterRoutesCoversEveryRoute(t *testing.T) {
+	r := newTestRouter(&caller{})
+	listed := make(map[string]bool, len(routeTable))
+	for _, route := range routeTable {
+		path := strings.ReplaceAll(route.path, "/google/", "/:provider/")
+		listed[route.method+" "+path] = true
+	}
+	for _, info := range r.Routes() {
+		if !listed[info.Method+" "+info.Path] {
+			t.Errorf("%s %s has no entry in routeTable", info.Method, info.Path)
+		}
+	}
+}
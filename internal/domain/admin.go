@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserFilter narrows the admin user listing. Query matches part of the email address.
+type UserFilter struct {
+	Query     string
+	Role      string
+	Suspended *bool
+	PaginationParams
+}
+
+type PaginatedUsersResponse struct {
+	Users      []User         `json:"users"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+type ImpersonationToken struct {
+	AccessToken string    `json:"token"`
+	SessionID   uuid.UUID `json:"session_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	User        *User     `json:"user"`
+}
+
+type AdminUsecase interface {
+	ListUsers(ctx context.Context, filter UserFilter) (*PaginatedUsersResponse, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*User, error)
+	ChangeRole(ctx context.Context, adminID, userID uuid.UUID, role string) (*User, error)
+	SuspendUser(ctx context.Context, adminID, userID uuid.UUID) (*User, error)
+	UnsuspendUser(ctx context.Context, adminID, userID uuid.UUID) (*User, error)
+	ForceLogout(ctx context.Context, adminID, userID uuid.UUID) error
+	// Impersonate opens a short-lived session as userID on behalf of adminID. It cannot be refreshed.
+	Impersonate(ctx context.Context, adminID, userID uuid.UUID, client ClientInfo) (*ImpersonationToken, error)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes an API key can be granted. A key never allows more than its creator could do.
+const (
+	ScopeJobsRead          = "jobs:read"
+	ScopeJobsWrite         = "jobs:write"
+	ScopeApplicationsRead  = "applications:read"
+	ScopeApplicationsWrite = "applications:write"
+)
+
+// APIKeyPrefix starts every API key so the auth middleware can tell keys from JWTs.
+const APIKeyPrefix = "jpk_"
+
+// APIKey lets an integration act as the recruiter who created it, limited to its scopes.
+// Only the hash of the secret is stored; Prefix is kept so users can recognise a key.
+type APIKey struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"organization_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user_id"`
+	User           *User      `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	Name           string     `gorm:"not null" json:"name"`
+	Prefix         string     `gorm:"not null" json:"prefix"`
+	KeyHash        string     `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes         []string   `gorm:"type:text;serializer:json;not null" json:"scopes"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+}
+
+// IsActive reports whether the key can still authenticate requests.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope reports whether scope is one an API key can be granted.
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopeJobsRead, ScopeJobsWrite, ScopeApplicationsRead, ScopeApplicationsWrite:
+		return true
+	}
+	return false
+}
+
+// CreatedAPIKey is returned once on creation; the plaintext key cannot be retrieved later.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	// GetByHash returns the key with its User loaded.
+	GetByHash(ctx context.Context, hash string) (*APIKey, error)
+	GetByID(ctx context.Context, orgID, id uuid.UUID) (*APIKey, error)
+	ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]APIKey, error)
+	ListByUser(ctx context.Context, orgID, userID uuid.UUID) ([]APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+type APIKeyUsecase interface {
+	CreateKey(ctx context.Context, actor Actor, name string, scopes []string, expiresAt *time.Time) (*CreatedAPIKey, error)
+	ListKeys(ctx context.Context, actor Actor) ([]APIKey, error)
+	RevokeKey(ctx context.Context, actor Actor, id uuid.UUID) error
+	// Authenticate resolves a plaintext key to its record, returning ErrUnauthorized for
+	// unknown, expired or revoked keys and ErrAccountSuspended when the owner is suspended.
+	Authenticate(ctx context.Context, rawKey string) (*APIKey, error)
+}
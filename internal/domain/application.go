@@ -9,19 +9,37 @@ import (
 )
 
 type Application struct {
-	ID           uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	JobID        uuid.UUID      `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"job_id" binding:"required"`
-	Job          *Job           `gorm:"foreignKey:JobID;references:ID" json:"job,omitempty"`
-	SeekerID     uuid.UUID      `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"seeker_id"`
-	Seeker       *User          `gorm:"foreignKey:SeekerID;references:ID" json:"seeker,omitempty"`
-	Status       string         `gorm:"default:'PENDING'" json:"status"` // PENDING, PROCESS, ACCEPTED, REJECTED
-	ResumeURL    string         `json:"resume_url" binding:"required"`
-	CoverLetter  string         `gorm:"type:text" json:"cover_letter"`
-	LinkedInURL  string         `json:"linkedin_url"`
-	PortfolioURL string         `json:"portfolio_url"`
+	ID                uuid.UUID         `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt    `gorm:"index" json:"deleted_at"`
+	JobID             uuid.UUID         `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"job_id" binding:"required"`
+	Job               *Job              `gorm:"foreignKey:JobID;references:ID" json:"job,omitempty"`
+	JobRevisionID     *uuid.UUID        `gorm:"type:uuid;index" json:"job_revision_id"`
+	JobRevision       *JobRevision      `gorm:"foreignKey:JobRevisionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"job_revision,omitempty"`
+	SeekerID          uuid.UUID         `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"seeker_id"`
+	Seeker            *User             `gorm:"foreignKey:SeekerID;references:ID" json:"seeker,omitempty"`
+	Status            string            `gorm:"default:'PENDING'" json:"status"` // PENDING, PROCESS, ACCEPTED, REJECTED
+	ResumeURL         string            `json:"resume_url" binding:"required"`
+	CoverLetter       string            `gorm:"type:text" json:"cover_letter"`
+	LinkedInURL       string            `json:"linkedin_url"`
+	PortfolioURL      string            `json:"portfolio_url"`
+	Answers           []ScreeningAnswer `gorm:"type:jsonb;serializer:json" json:"answers"`
+	Flagged           bool              `gorm:"not null;default:false" json:"flagged"`
+	FailedQuestionIDs []uuid.UUID       `gorm:"type:jsonb;serializer:json" json:"failed_question_ids"`
+	MatchScore        *int              `json:"match_score"`
+	Match             *MatchDetails     `gorm:"type:jsonb;serializer:json" json:"match"`
+}
+
+// Orders for a job's applicants. Applications from before match scoring have no score
+// and come last.
+const (
+	ApplicantSortNewest = "newest"
+	ApplicantSortMatch  = "match"
+)
+
+func IsValidApplicantSort(sort string) bool {
+	return sort == ApplicantSortNewest || sort == ApplicantSortMatch
 }
 
 const (
@@ -34,16 +52,16 @@ const (
 type ApplicationRepository interface {
 	Create(ctx context.Context, app *Application) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Application, error)
-	GetByJobID(ctx context.Context, jobID uuid.UUID) ([]Application, error)
+	GetByJobID(ctx context.Context, jobID uuid.UUID, sort string) ([]Application, error)
 	GetBySeekerID(ctx context.Context, seekerID uuid.UUID) ([]Application, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	GetDashboardStats(ctx context.Context, recruiterID uuid.UUID) (*DashboardStats, error)
+	GetDashboardStats(ctx context.Context, orgID uuid.UUID) (*DashboardStats, error)
 }
 
 type ApplicationUsecase interface {
-	ApplyJob(ctx context.Context, jobID, seekerID uuid.UUID, resumeURL, coverLetter, linkedInURL, portfolioURL string) error
-	ListApplications(ctx context.Context, userID uuid.UUID, role string) ([]Application, error)
-	ListJobApplicants(ctx context.Context, jobID, recruiterID uuid.UUID) ([]Application, error)
-	UpdateStatus(ctx context.Context, appID, recruiterID uuid.UUID, status string) error
-	GetDashboardStats(ctx context.Context, recruiterID uuid.UUID) (*DashboardStats, error)
+	ApplyJob(ctx context.Context, actor Actor, jobID uuid.UUID, resumeURL, coverLetter, linkedInURL, portfolioURL string, answers []ScreeningAnswer) error
+	ListApplications(ctx context.Context, actor Actor) ([]Application, error)
+	ListJobApplicants(ctx context.Context, actor Actor, jobID uuid.UUID, sort string) ([]Application, error)
+	UpdateStatus(ctx context.Context, actor Actor, appID uuid.UUID, status string) error
+	GetDashboardStats(ctx context.Context, actor Actor) (*DashboardStats, error)
 }
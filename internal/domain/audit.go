@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited actions.
+const (
+	AuditRegister               = "auth.register"
+	AuditLogin                  = "auth.login"
+	AuditLoginFailed            = "auth.login_failed"
+	AuditTwoFactorFailed        = "auth.2fa_failed"
+	AuditLogout                 = "auth.logout"
+	AuditSessionRevoked         = "auth.session_revoked"
+	AuditRefreshTokenReused     = "auth.refresh_token_reused"
+	AuditPasswordResetRequested = "auth.password_reset_requested"
+	AuditPasswordReset          = "auth.password_reset"
+	AuditEmailVerified          = "auth.email_verified"
+	AuditTwoFactorEnabled       = "auth.2fa_enabled"
+	AuditTwoFactorDisabled      = "auth.2fa_disabled"
+	AuditIdentityLinked         = "auth.identity_linked"
+	AuditIdentityUnlinked       = "auth.identity_unlinked"
+	AuditRoleChanged            = "admin.role_changed"
+	AuditUserSuspended          = "admin.user_suspended"
+	AuditUserUnsuspended        = "admin.user_unsuspended"
+	AuditForcedLogout           = "admin.force_logout"
+	AuditImpersonationStarted   = "impersonation.started"
+	AuditImpersonatedRequest    = "impersonation.request"
+	AuditJobUpdated             = "job.updated"
+	AuditJobStatusChanged       = "job.status_changed"
+	AuditJobDeleted             = "job.deleted"
+	AuditJobRestored            = "job.restored"
+	AuditJobPurged              = "job.purged"
+	AuditApplicationStatus      = "application.status_changed"
+	AuditProfileUpdated         = "profile.updated"
+)
+
+// Kinds of record an audit event can point at.
+const (
+	AuditTargetUser           = "user"
+	AuditTargetSession        = "session"
+	AuditTargetIdentity       = "identity"
+	AuditTargetJob            = "job"
+	AuditTargetApplication    = "application"
+	AuditTargetSeekerProfile  = "seeker_profile"
+	AuditTargetCompanyProfile = "company_profile"
+)
+
+// AuditChange is the value of one field before and after an update.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEvent is an append-only record of who did what. ActorID is nil when nobody could
+// be identified, such as a login attempt for an unknown email. During impersonation
+// ImpersonatorID is the admin; changes made as the impersonated user keep that user as
+// ActorID, while the impersonation.request entry for each request names the admin as
+// ActorID and the user in its metadata. The request ID, IP and impersonator are filled
+// in from the request context.
+type AuditEvent struct {
+	ID             uuid.UUID              `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt      time.Time              `gorm:"index" json:"created_at"`
+	ActorID        *uuid.UUID             `gorm:"type:uuid;index" json:"actor_id"`
+	ImpersonatorID *uuid.UUID             `gorm:"type:uuid" json:"impersonator_id,omitempty"`
+	Action         string                 `gorm:"not null;index" json:"action"`
+	TargetType     string                 `gorm:"index:idx_audit_events_target" json:"target_type,omitempty"`
+	TargetID       *uuid.UUID             `gorm:"type:uuid;index:idx_audit_events_target" json:"target_id,omitempty"`
+	Changes        map[string]AuditChange `gorm:"type:jsonb;serializer:json" json:"changes,omitempty"`
+	Metadata       map[string]string      `gorm:"type:jsonb;serializer:json" json:"metadata,omitempty"`
+	IP             string                 `json:"ip"`
+	RequestID      string                 `gorm:"index" json:"request_id"`
+}
+
+// AuditFilter narrows the admin audit log query. Zero values match everything.
+type AuditFilter struct {
+	ActorID    *uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   *uuid.UUID
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	PaginationParams
+}
+
+type PaginatedAuditEventsResponse struct {
+	Events     []AuditEvent   `json:"events"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// AuditLogger appends events to the audit trail.
+type AuditLogger interface {
+	Record(ctx context.Context, event AuditEvent) error
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, event *AuditEvent) error
+	List(ctx context.Context, filter AuditFilter) ([]AuditEvent, int64, error)
+}
+
+type AuditUsecase interface {
+	AuditLogger
+	ListEvents(ctx context.Context, filter AuditFilter) (*PaginatedAuditEventsResponse, error)
+}
+
+// RequestInfo describes the HTTP request a usecase is serving, for the audit trail.
+type RequestInfo struct {
+	RequestID      string
+	IP             string
+	ImpersonatorID *uuid.UUID
+}
+
+type requestInfoKey struct{}
+
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the request details stored in ctx, or zero values outside a request.
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
@@ -1,10 +1,45 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
-	ErrNotFound     = errors.New("record not found")
-	ErrUnauthorized = errors.New("unauthorized action")
-	ErrForbidden    = errors.New("forbidden action")
-	ErrBadRequest   = errors.New("bad request")
+	ErrNotFound         = errors.New("record not found")
+	ErrUnauthorized     = errors.New("unauthorized action")
+	ErrForbidden        = errors.New("forbidden action")
+	ErrBadRequest       = errors.New("bad request")
+	ErrTooManyRequests  = errors.New("too many requests")
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	ErrConflict         = errors.New("conflict")
+	ErrAccountSuspended = errors.New("account is suspended")
+	ErrJobNotOpen       = errors.New("job is not accepting applications")
+	ErrJobExpired       = errors.New("job has expired")
 )
+
+// RetryAfterError is a rate limit rejection that knows when the caller may try again.
+type RetryAfterError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return ErrTooManyRequests.Error()
+}
+
+func (e *RetryAfterError) Is(target error) bool {
+	return target == ErrTooManyRequests
+}
+
+// ValidationError is a bad request that tells the caller what to fix.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrBadRequest
+}
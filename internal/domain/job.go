@@ -9,26 +9,115 @@ import (
 )
 
 type Job struct {
-	ID          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	Title       string         `json:"title" binding:"required"`
-	Description string         `gorm:"type:text" json:"description" binding:"required"`
-	Category    string         `json:"category"`
-	JobType     string         `json:"job_type"`
-	Salary      string         `json:"salary"`
-	Benefits    []string       `gorm:"serializer:json" json:"benefits"`
-	RecruiterID uuid.UUID      `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"recruiter_id"`
-	Recruiter   *User          `gorm:"foreignKey:RecruiterID;references:ID" json:"-"`
-	Company     JobCompany     `gorm:"foreignKey:RecruiterID;references:UserID" json:"company"`
+	ID                   uuid.UUID           `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt            time.Time           `json:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt      `gorm:"index" json:"deleted_at"`
+	Title                string              `json:"title" binding:"required"`
+	Description          string              `gorm:"type:text" json:"description" binding:"required"`
+	Category             string              `json:"category"`
+	JobType              string              `json:"job_type"`
+	Salary               Salary              `gorm:"embedded;embeddedPrefix:salary_" json:"salary"`
+	SalaryLegacy         string              `gorm:"type:text" json:"salary_legacy,omitempty"` // free-text salary the migration could not fully read
+	Benefits             []string            `gorm:"serializer:json" json:"benefits"`
+	Questions            []ScreeningQuestion `gorm:"type:jsonb;serializer:json" json:"questions"`
+	RequiredSkills       []string            `gorm:"type:jsonb;serializer:json" json:"required_skills"`
+	PreferredSkills      []string            `gorm:"type:jsonb;serializer:json" json:"preferred_skills"`
+	MinExperienceYears   int                 `gorm:"not null;default:0" json:"min_experience_years"`
+	MinEducation         string              `gorm:"size:16" json:"min_education"`
+	Status               string              `gorm:"size:16;not null;default:draft;index" json:"status"`
+	PublishedAt          *time.Time          `json:"published_at"`
+	ClosedAt             *time.Time          `json:"closed_at"`
+	ExpiresAt            *time.Time          `gorm:"index" json:"expires_at"`
+	ApplicationDeadline  *time.Time          `json:"application_deadline"`
+	ExpiryReminderSentAt *time.Time          `json:"-"`
+	RecruiterID          uuid.UUID           `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"recruiter_id"`
+	Recruiter            *User               `gorm:"foreignKey:RecruiterID;references:ID" json:"-"`
+	OrganizationID       uuid.UUID           `gorm:"type:uuid;index;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"organization_id"`
+	Organization         *Organization       `gorm:"foreignKey:OrganizationID;references:ID" json:"-"`
+	Company              JobCompany          `gorm:"foreignKey:OrganizationID;references:OrganizationID" json:"company"`
+}
+
+// Job lifecycle states. Jobs start as drafts; only published jobs are listed publicly
+// and accept applications, and archived jobs are final.
+const (
+	JobStatusDraft     = "draft"
+	JobStatusPublished = "published"
+	JobStatusPaused    = "paused"
+	JobStatusClosed    = "closed"
+	JobStatusArchived  = "archived"
+)
+
+// jobTransitions lists the states each job state may move to.
+var jobTransitions = map[string][]string{
+	JobStatusDraft:     {JobStatusPublished, JobStatusArchived},
+	JobStatusPublished: {JobStatusPaused, JobStatusClosed},
+	JobStatusPaused:    {JobStatusPublished, JobStatusClosed},
+	JobStatusClosed:    {JobStatusPublished, JobStatusArchived},
+	JobStatusArchived:  {},
+}
+
+func IsValidJobStatus(status string) bool {
+	_, ok := jobTransitions[status]
+	return ok
+}
+
+// CanTransitionJob reports whether a job in state from may move to state to.
+func CanTransitionJob(from, to string) bool {
+	for _, next := range jobTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsApplications reports whether the job is published and neither its deadline nor
+// its expiry has passed, even if the scheduler has not closed it yet.
+func (j *Job) AcceptsApplications(now time.Time) bool {
+	if j.Status != JobStatusPublished {
+		return false
+	}
+	if j.ApplicationDeadline != nil && !now.Before(*j.ApplicationDeadline) {
+		return false
+	}
+	return j.ExpiresAt == nil || now.Before(*j.ExpiresAt)
+}
+
+// IsExpired reports whether the job's expiry has passed.
+func (j *Job) IsExpired(now time.Time) bool {
+	return j.ExpiresAt != nil && !now.Before(*j.ExpiresAt)
+}
+
+// IsPublic reports whether the job may be shown outside its organization. Paused and
+// closed jobs stay visible so existing links keep working.
+func (j *Job) IsPublic() bool {
+	return j.Status == JobStatusPublished || j.Status == JobStatusPaused || j.Status == JobStatusClosed
+}
+
+// Pay periods a salary can be quoted in.
+const (
+	PayPeriodHour  = "hour"
+	PayPeriodMonth = "month"
+	PayPeriodYear  = "year"
+)
+
+// Salary is the advertised pay range in whole units of an ISO 4217 currency per
+// Period. Min and Max are either both set or both nil, e.g. when the job only says
+// the pay is negotiable.
+type Salary struct {
+	Min        *int64 `json:"min"`
+	Max        *int64 `json:"max"`
+	Currency   string `gorm:"size:3" json:"currency"`
+	Period     string `gorm:"size:8" json:"period"`
+	Negotiable bool   `gorm:"not null;default:false" json:"negotiable"`
 }
 
 type JobCompany struct {
-	UserID      uuid.UUID `gorm:"column:user_id;type:uuid" json:"-"`
-	CompanyName string    `json:"company_name"`
-	Location    string    `json:"location"`
-	LogoURL     string    `json:"logo_url"`
+	OrganizationID uuid.UUID `gorm:"column:organization_id;type:uuid" json:"-"`
+	CompanyName    string    `json:"company_name"`
+	Location       string    `json:"location"`
+	LogoURL        string    `json:"logo_url"`
 }
 
 func (JobCompany) TableName() string {
@@ -49,23 +138,126 @@ type PaginationMeta struct {
 	HasPrev      bool  `json:"has_prev"`
 }
 
+// Orderings accepted by the public job listing.
+const (
+	JobSortRelevance  = "relevance"
+	JobSortNewest     = "newest"
+	JobSortSalaryDesc = "salary_desc"
+	JobSortSalaryAsc  = "salary_asc"
+)
+
+func IsValidJobSort(sort string) bool {
+	switch sort {
+	case JobSortRelevance, JobSortNewest, JobSortSalaryDesc, JobSortSalaryAsc:
+		return true
+	}
+	return false
+}
+
+// JobFilter narrows the public job listing. Empty fields match every job; Query is a
+// full-text search over title, description, category and benefits, and Location matches
+// part of the company location. MinSalary and MaxSalary are yearly amounts matched
+// against each job's range normalized to a year, in the job's own currency, so they
+// are best combined with Currency.
+type JobFilter struct {
+	Query            string     `json:"q,omitempty"`
+	Category         string     `json:"category,omitempty"`
+	JobType          string     `json:"job_type,omitempty"`
+	Location         string     `json:"location,omitempty"`
+	RecruiterID      *uuid.UUID `json:"recruiter_id,omitempty"`
+	Currency         string     `json:"currency,omitempty"`
+	MinSalary        *int64     `json:"min_salary,omitempty"`
+	MaxSalary        *int64     `json:"max_salary,omitempty"`
+	Sort             string     `json:"sort"`
+	PaginationParams `json:"-"`
+}
+
 type PaginatedJobsResponse struct {
 	Jobs       []Job          `json:"jobs"`
 	Pagination PaginationMeta `json:"pagination"`
+	Filters    JobFilter      `json:"filters"`
+}
+
+// Markers the repository puts around matched words in search highlights. They are
+// control characters, so they survive HTML escaping and are then turned into <mark>
+// tags; a stray one in a posting can at worst produce an extra <mark>.
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// JobSearchResult is a job matched by full-text search. Highlight fields are HTML with
+// matched words wrapped in <mark>; the rest of the text is escaped.
+type JobSearchResult struct {
+	Job
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+type JobSearchResponse struct {
+	Results    []JobSearchResult `json:"results"`
+	Pagination PaginationMeta    `json:"pagination"`
+	Filters    JobFilter         `json:"filters"`
 }
 
 type JobRepository interface {
+	// Create stores the job along with its first revision.
 	Create(ctx context.Context, job *Job) error
-	Update(ctx context.Context, job *Job) error
-	GetAll(ctx context.Context, params PaginationParams) ([]Job, int64, error)
+	// UpdateStatus writes the job's status, published_at and closed_at, provided its status
+	// is still from; otherwise it returns ErrConflict.
+	UpdateStatus(ctx context.Context, job *Job, from string) error
+	// UpdateContent writes the job's content, leaving its status, ownership and deletion
+	// alone, and appends it as the next revision. A changed expiry clears the reminder.
+	UpdateContent(ctx context.Context, job *Job, editorID uuid.UUID) error
+	ListRevisions(ctx context.Context, jobID uuid.UUID) ([]JobRevision, error)
+	GetLatestRevision(ctx context.Context, jobID uuid.UUID) (*JobRevision, error)
+	GetAll(ctx context.Context, filter JobFilter) ([]Job, int64, error)
+	// Search ranks jobs matching filter.Query, best match first.
+	Search(ctx context.Context, filter JobFilter) ([]JobSearchResult, int64, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Job, error)
+	// GetByIDWithDeleted also finds soft-deleted jobs.
+	GetByIDWithDeleted(ctx context.Context, id uuid.UUID) (*Job, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	// Purge removes the job for good, returning ErrConflict while any application,
+	// even a deleted one, still points at it.
+	Purge(ctx context.Context, id uuid.UUID) error
 	GetByRecruiterID(ctx context.Context, recruiterID uuid.UUID) ([]Job, error)
+	GetByOrganizationID(ctx context.Context, orgID uuid.UUID) ([]Job, error)
+	// ListExpired returns published and paused jobs whose expiry has passed.
+	ListExpired(ctx context.Context, now time.Time) ([]Job, error)
+	// CloseIfExpired closes the job unless it was closed, reopened or extended meanwhile,
+	// reporting whether it did.
+	CloseIfExpired(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+	// ListExpiringSoon returns published and paused jobs expiring by the given time that have not had a
+	// reminder yet, with their recruiter loaded.
+	ListExpiringSoon(ctx context.Context, now, by time.Time) ([]Job, error)
+	MarkExpiryReminderSent(ctx context.Context, id uuid.UUID, at time.Time) error
 }
 
 type JobUsecase interface {
-	CreateJob(ctx context.Context, title, description, category, jobType, salary string, benefits []string, recruiterID uuid.UUID) error
-	UpdateJob(ctx context.Context, id, recruiterID uuid.UUID, title, description, category, jobType, salary string, benefits []string) error
-	ListJobs(ctx context.Context, params PaginationParams) (*PaginatedJobsResponse, error)
-	GetJob(ctx context.Context, id uuid.UUID) (*Job, error)
-	ListJobsByRecruiter(ctx context.Context, recruiterID uuid.UUID) ([]Job, error)
+	// CreateJob and UpdateJob take the job's content, its screening questions, skill and
+	// experience requirements and its dates from job, and record the result as a revision.
+	// UpdateJob returns ErrConflict for an archived job.
+	CreateJob(ctx context.Context, actor Actor, job *Job) error
+	UpdateJob(ctx context.Context, actor Actor, id uuid.UUID, input *Job) error
+	ListJobs(ctx context.Context, filter JobFilter) (*PaginatedJobsResponse, error)
+	SearchJobs(ctx context.Context, filter JobFilter) (*JobSearchResponse, error)
+	// ChangeJobStatus moves the job through its lifecycle, returning ErrConflict for a
+	// transition the state machine does not allow.
+	ChangeJobStatus(ctx context.Context, actor Actor, id uuid.UUID, status string) (*Job, error)
+	GetJob(ctx context.Context, actor Actor, id uuid.UUID) (*Job, error)
+	// DeleteJob hides the job everywhere but keeps it and its applications, so
+	// RestoreJob can bring it back. Only PurgeJob, for admins, removes it for good.
+	DeleteJob(ctx context.Context, actor Actor, id uuid.UUID) error
+	RestoreJob(ctx context.Context, actor Actor, id uuid.UUID) (*Job, error)
+	PurgeJob(ctx context.Context, actor Actor, id uuid.UUID) error
+	// ListRevisions returns the job's revisions, newest first, each diffed against the one before.
+	ListRevisions(ctx context.Context, actor Actor, id uuid.UUID) ([]JobRevisionDiff, error)
+	ListJobsByRecruiter(ctx context.Context, actor Actor, recruiterID uuid.UUID) ([]Job, error)
+	ListOrganizationJobs(ctx context.Context, actor Actor) ([]Job, error)
+	// CloseExpiredJobs and SendExpiryReminders are run by the background scheduler.
+	CloseExpiredJobs(ctx context.Context) error
+	SendExpiryReminders(ctx context.Context) error
 }
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobContent is the part of a job its recruiters write, as it stood at one revision.
+type JobContent struct {
+	Title               string              `json:"title"`
+	Description         string              `json:"description"`
+	Category            string              `json:"category"`
+	JobType             string              `json:"job_type"`
+	Salary              Salary              `json:"salary"`
+	Benefits            []string            `json:"benefits"`
+	Questions           []ScreeningQuestion `json:"questions"`
+	RequiredSkills      []string            `json:"required_skills"`
+	PreferredSkills     []string            `json:"preferred_skills"`
+	MinExperienceYears  int                 `json:"min_experience_years"`
+	MinEducation        string              `json:"min_education"`
+	ExpiresAt           *time.Time          `json:"expires_at"`
+	ApplicationDeadline *time.Time          `json:"application_deadline"`
+}
+
+// JobContentFields are the JSON names of the JobContent fields, in display order.
+var JobContentFields = []string{"title", "description", "category", "job_type", "salary", "benefits", "questions", "required_skills", "preferred_skills", "min_experience_years", "min_education", "expires_at", "application_deadline"}
+
+func (j *Job) Content() JobContent {
+	return JobContent{
+		Title:               j.Title,
+		Description:         j.Description,
+		Category:            j.Category,
+		JobType:             j.JobType,
+		Salary:              j.Salary,
+		Benefits:            j.Benefits,
+		Questions:           j.Questions,
+		RequiredSkills:      j.RequiredSkills,
+		PreferredSkills:     j.PreferredSkills,
+		MinExperienceYears:  j.MinExperienceYears,
+		MinEducation:        j.MinEducation,
+		ExpiresAt:           j.ExpiresAt,
+		ApplicationDeadline: j.ApplicationDeadline,
+	}
+}
+
+// JobRevision is an immutable snapshot of a job's content, numbered from 1 per job and
+// stored each time the content changes. Applications point at the revision the
+// candidate applied to.
+type JobRevision struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	JobID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_job_revisions_job_number" json:"job_id"`
+	Job       *Job       `gorm:"foreignKey:JobID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Number    int        `gorm:"not null;uniqueIndex:idx_job_revisions_job_number" json:"number"`
+	EditorID  *uuid.UUID `gorm:"type:uuid" json:"editor_id"`
+	Editor    *User      `gorm:"foreignKey:EditorID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	Content   JobContent `gorm:"type:jsonb;serializer:json" json:"content"`
+}
+
+// JobRevisionDiff is a revision with the fields that changed since the previous one;
+// Changes is empty for the first revision.
+type JobRevisionDiff struct {
+	JobRevision
+	Changes map[string]AuditChange `json:"changes"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttempt counts recent failed logins for a throttling key such as "email:<address>" or "ip:<addr>".
+type LoginAttempt struct {
+	Key          string     `gorm:"primaryKey" json:"key"`
+	Failures     int        `gorm:"not null;default:0" json:"failures"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until"`
+}
+
+// ClientInfo describes where a request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type LoginAttemptRepository interface {
+	// Get returns nil when the key has no recorded failures.
+	Get(ctx context.Context, key string) (*LoginAttempt, error)
+	// RecordFailure increments the failure count, starting over when the previous
+	// failure or lockout ended more than window ago.
+	RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
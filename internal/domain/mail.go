@@ -0,0 +1,7 @@
+package domain
+
+import "context"
+
+type MailSender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
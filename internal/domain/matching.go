@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Education levels a job can require, lowest first.
+const (
+	EducationHighSchool = "high_school"
+	EducationDiploma    = "diploma"
+	EducationBachelor   = "bachelor"
+	EducationMaster     = "master"
+	EducationDoctorate  = "doctorate"
+)
+
+var educationRank = map[string]int{
+	EducationHighSchool: 1,
+	EducationDiploma:    2,
+	EducationBachelor:   3,
+	EducationMaster:     4,
+	EducationDoctorate:  5,
+}
+
+func IsValidEducationLevel(level string) bool {
+	_, ok := educationRank[level]
+	return ok
+}
+
+// degreeKeywords recognise the level of a free-text degree, including the Indonesian
+// SMA/D3/S1/S2/S3 names. They are matched as whole words with dots and possessives
+// removed, so "Ph.D." reads as "phd" and "Bachelor's" as "bachelor". Higher levels are
+// checked first, except that high school comes before diploma so a "high school
+// diploma" is not taken for one.
+var degreeKeywords = []struct {
+	level    string
+	keywords []string
+}{
+	{EducationDoctorate, []string{"phd", "doctor", "doctorate", "doctoral", "doktor", "s3"}},
+	{EducationMaster, []string{"master", "masters", "magister", "msc", "mba", "s2"}},
+	{EducationBachelor, []string{"bachelor", "bachelors", "sarjana", "bsc", "ba", "bs", "s1"}},
+	{EducationHighSchool, []string{"high school", "sma", "smk", "ged"}},
+	{EducationDiploma, []string{"diploma", "associate", "d1", "d2", "d3", "d4"}},
+}
+
+// degreePunctuation is dropped from a degree before it is split into words.
+var degreePunctuation = strings.NewReplacer(".", "", "'s", "", "’s", "")
+
+// EducationLevel guesses the level of a free-text degree, or returns "" when it cannot.
+func EducationLevel(degree string) string {
+	words := strings.FieldsFunc(degreePunctuation.Replace(strings.ToLower(degree)), func(r rune) bool {
+		return r == ' ' || r == ',' || r == '(' || r == ')' || r == '/' || r == '-'
+	})
+	text := " " + strings.Join(words, " ") + " "
+	for _, d := range degreeKeywords {
+		for _, keyword := range d.keywords {
+			if strings.Contains(text, " "+keyword+" ") {
+				return d.level
+			}
+		}
+	}
+	return ""
+}
+
+// skillAliases maps common spellings onto one tag.
+var skillAliases = map[string]string{
+	"golang":              "go",
+	"js":                  "javascript",
+	"ts":                  "typescript",
+	"nodejs":              "node.js",
+	"node":                "node.js",
+	"reactjs":             "react",
+	"react.js":            "react",
+	"vuejs":               "vue",
+	"vue.js":              "vue",
+	"postgres":            "postgresql",
+	"psql":                "postgresql",
+	"k8s":                 "kubernetes",
+	"c sharp":             "c#",
+	"csharp":              "c#",
+	"cpp":                 "c++",
+	"py":                  "python",
+	"amazon web services": "aws",
+}
+
+// NormalizeSkill turns a skill into its tag: lower case, single spaces, common aliases
+// resolved.
+func NormalizeSkill(skill string) string {
+	tag := strings.Join(strings.Fields(strings.ToLower(skill)), " ")
+	if alias, ok := skillAliases[tag]; ok {
+		return alias
+	}
+	return tag
+}
+
+// NormalizeSkills normalizes each skill, dropping blanks and duplicates but keeping order.
+func NormalizeSkills(skills []string) []string {
+	if skills == nil {
+		return nil
+	}
+	tags := make([]string, 0, len(skills))
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		tag := NormalizeSkill(skill)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// MatchDetails explains an application's match score.
+type MatchDetails struct {
+	MatchedSkills          []string `json:"matched_skills"`
+	MissingSkills          []string `json:"missing_skills"`
+	MatchedPreferredSkills []string `json:"matched_preferred_skills"`
+	MissingPreferredSkills []string `json:"missing_preferred_skills"`
+	ExperienceMonths       int      `json:"experience_months"`
+	EducationLevel         string   `json:"education_level"`
+}
+
+// Weights of the parts of a match score, adding up to 100. A part the job sets no
+// requirement for is fully met.
+const (
+	matchWeightRequired   = 60
+	matchWeightPreferred  = 15
+	matchWeightExperience = 15
+	matchWeightEducation  = 10
+)
+
+// ScoreMatch scores from 0 to 100 how well a seeker's profile fits the job content. The
+// profile may be nil for a seeker who never filled one in.
+func ScoreMatch(job JobContent, profile *SeekerProfile, now time.Time) (int, MatchDetails) {
+	if profile == nil {
+		profile = &SeekerProfile{}
+	}
+	var details MatchDetails
+
+	has := make(map[string]bool, len(profile.Skills))
+	for _, tag := range NormalizeSkills(profile.Skills) {
+		has[tag] = true
+	}
+	details.MatchedSkills, details.MissingSkills = splitSkills(job.RequiredSkills, has)
+	details.MatchedPreferredSkills, details.MissingPreferredSkills = splitSkills(job.PreferredSkills, has)
+
+	details.ExperienceMonths = experienceMonths(profile.Experiences, now)
+	best := 0
+	for _, edu := range profile.Educations {
+		if level := EducationLevel(edu.Degree); educationRank[level] > best {
+			best = educationRank[level]
+			details.EducationLevel = level
+		}
+	}
+
+	score := matchWeightRequired*fraction(len(details.MatchedSkills), len(job.RequiredSkills)) +
+		matchWeightPreferred*fraction(len(details.MatchedPreferredSkills), len(job.PreferredSkills)) +
+		matchWeightExperience*fraction(details.ExperienceMonths, job.MinExperienceYears*12)
+	if job.MinEducation == "" || best >= educationRank[job.MinEducation] {
+		score += matchWeightEducation
+	}
+	return int(math.Round(score)), details
+}
+
+func splitSkills(wanted []string, has map[string]bool) (matched, missing []string) {
+	matched, missing = []string{}, []string{}
+	for _, tag := range wanted {
+		if has[tag] {
+			matched = append(matched, tag)
+		} else {
+			missing = append(missing, tag)
+		}
+	}
+	return matched, missing
+}
+
+// fraction is got/want capped at 1, and 1 when nothing is wanted.
+func fraction(got, want int) float64 {
+	if want <= 0 || got >= want {
+		return 1
+	}
+	return float64(got) / float64(want)
+}
+
+// averageMonth is a twelfth of a year, so twelve months of experience make a year.
+const averageMonth = 730*time.Hour + 30*time.Minute
+
+// experienceMonths adds up the months covered by the experiences, rounded to whole
+// months, counting overlapping jobs once and a missing end date as ongoing.
+func experienceMonths(experiences []Experience, now time.Time) int {
+	type span struct{ start, end time.Time }
+	spans := make([]span, 0, len(experiences))
+	for _, exp := range experiences {
+		end := now
+		if exp.EndDate != nil && exp.EndDate.Before(now) {
+			end = *exp.EndDate
+		}
+		if exp.StartDate.IsZero() || !exp.StartDate.Before(end) {
+			continue
+		}
+		spans = append(spans, span{exp.StartDate, end})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	var total time.Duration
+	var current *span
+	for i := range spans {
+		s := spans[i]
+		if current != nil && !s.start.After(current.end) {
+			if s.end.After(current.end) {
+				current.end = s.end
+			}
+			continue
+		}
+		if current != nil {
+			total += current.end.Sub(current.start)
+		}
+		current = &s
+	}
+	if current != nil {
+		total += current.end.Sub(current.start)
+	}
+	return int(math.Round(float64(total) / float64(averageMonth)))
+}
@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEducationLevel(t *testing.T) {
+	tests := []struct {
+		degree string
+		want   string
+	}{
+		{degree: "PhD in Physics", want: EducationDoctorate},
+		{degree: "Ph.D.", want: EducationDoctorate},
+		{degree: "Doctoral programme", want: EducationDoctorate},
+		{degree: "S3 Ilmu Komputer", want: EducationDoctorate},
+		{degree: "Master's degree", want: EducationMaster},
+		{degree: "M.Sc. Data Science", want: EducationMaster},
+		{degree: "MBA", want: EducationMaster},
+		{degree: "Magister Manajemen (S2)", want: EducationMaster},
+		{degree: "Bachelor's in Economics", want: EducationBachelor},
+		{degree: "B.Sc. Computer Science", want: EducationBachelor},
+		{degree: "BA Hons", want: EducationBachelor},
+		{degree: "B.S.", want: EducationBachelor},
+		{degree: "S1 Teknik Informatika", want: EducationBachelor},
+		{degree: "Sarjana Komputer", want: EducationBachelor},
+		{degree: "D3 Akuntansi", want: EducationDiploma},
+		{degree: "Associate of Arts", want: EducationDiploma},
+		{degree: "SMA Negeri 1", want: EducationHighSchool},
+		{degree: "High School Diploma", want: EducationHighSchool},
+		{degree: "GED", want: EducationHighSchool},
+		// Keywords only count as whole words.
+		{degree: "Basic Accounting Course", want: ""},
+		{degree: "Bootcamp in Bash scripting", want: ""},
+		{degree: "Embassy internship", want: ""},
+		{degree: "Managed services certificate", want: ""},
+		{degree: "CS101", want: ""},
+		{degree: "Smart contracts", want: ""},
+		{degree: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.degree, func(t *testing.T) {
+			if got := EducationLevel(tt.degree); got != tt.want {
+				t.Fatalf("EducationLevel(%q) = %q, want %q", tt.degree, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSkills(t *testing.T) {
+	tests := []struct {
+		name   string
+		skills []string
+		want   []string
+	}{
+		{name: "nil stays nil", skills: nil, want: nil},
+		{name: "aliases and case", skills: []string{"Golang", "K8s", "PostgreSQL"}, want: []string{"go", "kubernetes", "postgresql"}},
+		{name: "duplicates after normalizing", skills: []string{"Go", "golang", "GO"}, want: []string{"go"}},
+		{name: "blanks and spacing", skills: []string{"  ", "Amazon  Web Services", ""}, want: []string{"aws"}},
+		{name: "order kept", skills: []string{"react", "node", "ts"}, want: []string{"react", "node.js", "typescript"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeSkills(tt.skills); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("NormalizeSkills(%q) = %q, want %q", tt.skills, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExperienceMonths(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month) time.Time { return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC) }
+	until := func(year int, month time.Month) *time.Time { d := date(year, month); return &d }
+
+	tests := []struct {
+		name        string
+		experiences []Experience
+		want        int
+	}{
+		{name: "none", want: 0},
+		{
+			name:        "one closed job",
+			experiences: []Experience{{StartDate: date(2020, 1), EndDate: until(2022, 1)}},
+			want:        24,
+		},
+		{
+			name:        "current job runs until now",
+			experiences: []Experience{{StartDate: date(2025, 1)}},
+			want:        12,
+		},
+		{
+			name:        "end date in the future counts until now",
+			experiences: []Experience{{StartDate: date(2025, 7), EndDate: until(2027, 1)}},
+			want:        6,
+		},
+		{
+			name: "overlapping jobs count once",
+			experiences: []Experience{
+				{StartDate: date(2020, 1), EndDate: until(2021, 1)},
+				{StartDate: date(2020, 7), EndDate: until(2021, 7)},
+			},
+			want: 18,
+		},
+		{
+			name: "job inside another",
+			experiences: []Experience{
+				{StartDate: date(2019, 1), EndDate: until(2022, 1)},
+				{StartDate: date(2020, 1), EndDate: until(2021, 1)},
+			},
+			want: 36,
+		},
+		{
+			name: "side job during a current one",
+			experiences: []Experience{
+				{StartDate: date(2024, 1)},
+				{StartDate: date(2024, 6), EndDate: until(2025, 6)},
+			},
+			want: 24,
+		},
+		{
+			name: "gap between jobs is not counted",
+			experiences: []Experience{
+				{StartDate: date(2022, 1), EndDate: until(2023, 1)},
+				{StartDate: date(2018, 1), EndDate: until(2019, 1)},
+			},
+			want: 24,
+		},
+		{
+			name: "missing start and reversed dates are skipped",
+			experiences: []Experience{
+				{EndDate: until(2020, 1)},
+				{StartDate: date(2021, 1), EndDate: until(2020, 1)},
+				{StartDate: date(2027, 1)},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := experienceMonths(tt.experiences, now); got != tt.want {
+				t.Fatalf("experienceMonths = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreMatch(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	threeYears := []Experience{{StartDate: now.AddDate(-3, 0, 0)}}
+
+	tests := []struct {
+		name        string
+		job         JobContent
+		profile     *SeekerProfile
+		want        int
+		wantMissing []string
+		wantLevel   string
+	}{
+		{
+			name: "a job with no requirements is fully met by anyone",
+			job:  JobContent{},
+			want: 100,
+		},
+		{
+			name:    "a job with no requirements and an empty profile",
+			job:     JobContent{},
+			profile: &SeekerProfile{},
+			want:    100,
+		},
+		{
+			name:        "no profile only scores the parts the job leaves unset",
+			job:         JobContent{RequiredSkills: []string{"go", "sql"}, MinExperienceYears: 2, MinEducation: EducationBachelor},
+			want:        15,
+			wantMissing: []string{"go", "sql"},
+		},
+		{
+			name: "everything met",
+			job: JobContent{
+				RequiredSkills:     []string{"go", "postgresql"},
+				PreferredSkills:    []string{"kubernetes"},
+				MinExperienceYears: 3,
+				MinEducation:       EducationBachelor,
+			},
+			profile: &SeekerProfile{
+				Skills:      []string{"Golang", "Postgres", "k8s"},
+				Experiences: threeYears,
+				Educations:  []Education{{Degree: "S1 Informatika"}},
+			},
+			want:        100,
+			wantMissing: []string{},
+			wantLevel:   EducationBachelor,
+		},
+		{
+			name: "partial skills and experience",
+			job: JobContent{
+				RequiredSkills:     []string{"go", "postgresql"},
+				PreferredSkills:    []string{"kubernetes", "aws"},
+				MinExperienceYears: 6,
+				MinEducation:       EducationMaster,
+			},
+			profile: &SeekerProfile{
+				Skills:      []string{"go", "aws"},
+				Experiences: threeYears,
+				Educations:  []Education{{Degree: "Bachelor of Science"}, {Degree: "SMA"}},
+			},
+			// 60*1/2 + 15*1/2 + 15*1/2 + 0 for education below a master's.
+			want:        45,
+			wantMissing: []string{"postgresql"},
+			wantLevel:   EducationBachelor,
+		},
+		{
+			name:        "more experience than asked is capped",
+			job:         JobContent{MinExperienceYears: 1},
+			profile:     &SeekerProfile{Experiences: threeYears},
+			want:        100,
+			wantMissing: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, details := ScoreMatch(tt.job, tt.profile, now)
+			if got != tt.want {
+				t.Fatalf("score = %d, want %d (%+v)", got, tt.want, details)
+			}
+			wantMissing := tt.wantMissing
+			if wantMissing == nil {
+				wantMissing = []string{}
+			}
+			if !reflect.DeepEqual(details.MissingSkills, wantMissing) {
+				t.Fatalf("missing skills = %q, want %q", details.MissingSkills, wantMissing)
+			}
+			if details.EducationLevel != tt.wantLevel {
+				t.Fatalf("education level = %q, want %q", details.EducationLevel, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestFraction(t *testing.T) {
+	tests := []struct {
+		got, want int
+		expected  float64
+	}{
+		{got: 0, want: 0, expected: 1},
+		{got: 3, want: 0, expected: 1},
+		{got: 0, want: -1, expected: 1},
+		{got: 1, want: 4, expected: 0.25},
+		{got: 5, want: 4, expected: 1},
+	}
+	for _, tt := range tests {
+		if f := fraction(tt.got, tt.want); f != tt.expected {
+			t.Errorf("fraction(%d, %d) = %v, want %v", tt.got, tt.want, f, tt.expected)
+		}
+	}
+}
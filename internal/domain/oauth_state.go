@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthState remembers a pending social login between the redirect to the provider
+// and its callback. It is deleted when the callback consumes it.
+type OAuthState struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StateHash    string     `gorm:"uniqueIndex;not null" json:"-"`
+	Provider     string     `gorm:"not null" json:"provider"`
+	CodeVerifier string     `gorm:"not null" json:"-"`
+	Nonce        string     `gorm:"not null" json:"-"`
+	LinkUserID   *uuid.UUID `gorm:"type:uuid" json:"link_user_id"` // set when a signed-in user is linking a provider
+	ExpiresAt    time.Time  `gorm:"index" json:"expires_at"`
+}
+
+// OAuthRedirect is where to send the browser to start a social login. State must
+// also be bound to the browser so the callback can tell it started the flow.
+type OAuthRedirect struct {
+	URL       string
+	State     string
+	ExpiresAt time.Time
+}
+
+type OAuthStateRepository interface {
+	Create(ctx context.Context, state *OAuthState) error
+	// Consume deletes and returns the state so it can only be used once.
+	Consume(ctx context.Context, stateHash string) (*OAuthState, error)
+}
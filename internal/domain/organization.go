@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Roles a user can hold inside an organization, from most to least privileged.
+const (
+	OrgRoleOwner     = "OWNER"
+	OrgRoleAdmin     = "ADMIN"
+	OrgRoleRecruiter = "RECRUITER"
+	OrgRoleViewer    = "VIEWER"
+)
+
+// Organization is a hiring team. It owns the company profile and the jobs its members post.
+type Organization struct {
+	ID        uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	Name      string         `gorm:"not null" json:"name"`
+}
+
+// OrganizationMember links a user to the one organization they work in.
+type OrganizationMember struct {
+	ID             uuid.UUID     `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	OrganizationID uuid.UUID     `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"organization_id"`
+	Organization   *Organization `gorm:"foreignKey:OrganizationID;references:ID" json:"organization,omitempty"`
+	UserID         uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user_id"`
+	User           *User         `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Role           string        `gorm:"not null" json:"role"` // OWNER, ADMIN, RECRUITER, VIEWER
+}
+
+// OrganizationInvitation is an emailed offer to join an organization. Only the token hash is stored.
+type OrganizationInvitation struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"organization_id"`
+	Email          string     `gorm:"not null;index" json:"email"`
+	Role           string     `gorm:"not null" json:"role"`
+	TokenHash      string     `gorm:"uniqueIndex;not null" json:"-"`
+	InvitedByID    uuid.UUID  `gorm:"type:uuid;not null" json:"invited_by_id"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+}
+
+// IsPending reports whether the invitation can still be accepted.
+func (i *OrganizationInvitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// IsValidOrgRole reports whether role is one of the organization roles.
+func IsValidOrgRole(role string) bool {
+	switch role {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleRecruiter, OrgRoleViewer:
+		return true
+	}
+	return false
+}
+
+type OrganizationRepository interface {
+	// Create stores the organization together with its first member.
+	Create(ctx context.Context, org *Organization, owner *OrganizationMember) error
+	Update(ctx context.Context, org *Organization) error
+	// GetMembership returns nil when the user does not belong to an organization.
+	GetMembership(ctx context.Context, userID uuid.UUID) (*OrganizationMember, error)
+	GetMember(ctx context.Context, orgID, memberID uuid.UUID) (*OrganizationMember, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]OrganizationMember, error)
+	CountOwners(ctx context.Context, orgID uuid.UUID) (int64, error)
+	CountMembers(ctx context.Context, orgID uuid.UUID) (int64, error)
+	// HasJobs counts deleted jobs too, since they still belong to the organization.
+	HasJobs(ctx context.Context, orgID uuid.UUID) (bool, error)
+	UpdateMemberRole(ctx context.Context, memberID uuid.UUID, role string) error
+	RemoveMember(ctx context.Context, memberID uuid.UUID) error
+	// Dissolve removes the organization's last member and deletes the organization.
+	Dissolve(ctx context.Context, orgID, memberID uuid.UUID) error
+	CreateInvitation(ctx context.Context, invitation *OrganizationInvitation) error
+	GetInvitationByHash(ctx context.Context, hash string) (*OrganizationInvitation, error)
+	ListPendingInvitations(ctx context.Context, orgID uuid.UUID, now time.Time) ([]OrganizationInvitation, error)
+	DeleteInvitation(ctx context.Context, orgID, invitationID uuid.UUID) error
+	// AcceptInvitation marks the invitation used and adds the member atomically. A non-nil
+	// leaving membership is the user's current organization, which is dissolved first.
+	AcceptInvitation(ctx context.Context, invitationID uuid.UUID, member, leaving *OrganizationMember) error
+}
+
+type OrganizationUsecase interface {
+	CreateOrganization(ctx context.Context, actor Actor, name string) (*OrganizationMember, error)
+	GetMyOrganization(ctx context.Context, actor Actor) (*OrganizationMember, error)
+	UpdateOrganization(ctx context.Context, actor Actor, name string) (*Organization, error)
+	ListMembers(ctx context.Context, actor Actor) ([]OrganizationMember, error)
+	ChangeMemberRole(ctx context.Context, actor Actor, memberID uuid.UUID, role string) error
+	RemoveMember(ctx context.Context, actor Actor, memberID uuid.UUID) error
+	Invite(ctx context.Context, actor Actor, email, role string) (*OrganizationInvitation, error)
+	ListInvitations(ctx context.Context, actor Actor) ([]OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, actor Actor, invitationID uuid.UUID) error
+	AcceptInvitation(ctx context.Context, actor Actor, token string) (*OrganizationMember, error)
+}
@@ -0,0 +1,175 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Actor is the authenticated user a usecase acts on behalf of. Membership is loaded
+// by the usecase before organization-scoped checks and is nil outside an organization.
+type Actor struct {
+	UserID     uuid.UUID
+	Role       string
+	Membership *OrganizationMember
+}
+
+func (a Actor) IsAdmin() bool {
+	return a.Role == RoleAdmin
+}
+
+type Action string
+
+const (
+	ActionCreateJob               Action = "job:create"
+	ActionUpdateJob               Action = "job:update"
+	ActionDeleteJob               Action = "job:delete"
+	ActionPurgeJob                Action = "job:purge"
+	ActionViewUnpublishedJob      Action = "job:view_unpublished"
+	ActionListRecruiterJobs       Action = "job:list_by_recruiter"
+	ActionViewApplicants          Action = "job:view_applicants"
+	ActionViewJobRevisions        Action = "job:view_revisions"
+	ActionViewKnockoutRules       Action = "job:view_knockout_rules"
+	ActionApplyJob                Action = "application:create"
+	ActionListOwnApplications     Action = "application:list_own"
+	ActionUpdateApplicationStatus Action = "application:update_status"
+	ActionViewDashboard           Action = "dashboard:view"
+	ActionViewOrganization        Action = "organization:view"
+	ActionManageOrganization      Action = "organization:manage"
+	ActionManageMember            Action = "organization:manage_member"
+	ActionGrantOwner              Action = "organization:grant_owner"
+	ActionCreateAPIKey            Action = "api_key:create"
+	ActionManageAPIKey            Action = "api_key:manage"
+)
+
+// RecruiterRef names the recruiter whose jobs are being listed.
+type RecruiterRef struct {
+	ID uuid.UUID
+}
+
+var (
+	// orgEditors may post and edit jobs and move applications through the pipeline.
+	orgEditors = []string{OrgRoleOwner, OrgRoleAdmin, OrgRoleRecruiter}
+	// orgManagers may edit the company profile, members and invitations.
+	orgManagers = []string{OrgRoleOwner, OrgRoleAdmin}
+	orgAnyRole  = []string{OrgRoleOwner, OrgRoleAdmin, OrgRoleRecruiter, OrgRoleViewer}
+)
+
+// Authorize reports whether actor may perform action on resource, returning ErrForbidden
+// when it may not. The resource is the object acted upon: a *Job for job and application
+// actions, an *OrganizationMember for member changes, an *APIKey for key changes, a
+// RecruiterRef for recruiter listings, and nil for everything else.
+func Authorize(actor Actor, action Action, resource interface{}) error {
+	switch action {
+	case ActionApplyJob, ActionListOwnApplications:
+		return requireRole(actor, RoleSeeker)
+
+	case ActionCreateJob:
+		if err := requireRole(actor, RoleRecruiter); err != nil {
+			return err
+		}
+		return requireOrgRole(actor, nil, orgEditors...)
+
+	case ActionViewDashboard, ActionViewOrganization:
+		return requireOrgRole(actor, nil, orgAnyRole...)
+
+	case ActionManageOrganization:
+		return requireOrgRole(actor, nil, orgManagers...)
+
+	case ActionGrantOwner:
+		return requireOrgRole(actor, nil, OrgRoleOwner)
+
+	case ActionCreateAPIKey:
+		// Keys act as their creator, so only members who can change jobs may mint them.
+		return requireOrgRole(actor, nil, orgEditors...)
+
+	case ActionManageAPIKey:
+		key, ok := resource.(*APIKey)
+		if !ok || key == nil {
+			return ErrForbidden
+		}
+		orgID := key.OrganizationID
+		if key.UserID == actor.UserID {
+			return requireOrgRole(actor, &orgID, orgAnyRole...)
+		}
+		return requireOrgRole(actor, &orgID, orgManagers...)
+
+	case ActionManageMember:
+		target, ok := resource.(*OrganizationMember)
+		if !ok || target == nil {
+			return ErrForbidden
+		}
+		orgID := target.OrganizationID
+		// Only owners may change or remove other owners.
+		if target.Role == OrgRoleOwner {
+			return requireOrgRole(actor, &orgID, OrgRoleOwner)
+		}
+		return requireOrgRole(actor, &orgID, orgManagers...)
+
+	case ActionPurgeJob:
+		if !actor.IsAdmin() {
+			return ErrForbidden
+		}
+		return nil
+
+	case ActionUpdateJob, ActionDeleteJob:
+		// Admins may edit or remove any posting to moderate it.
+		if actor.IsAdmin() {
+			return nil
+		}
+		return requireJobRole(actor, resource, orgEditors...)
+
+	case ActionViewUnpublishedJob, ActionViewJobRevisions, ActionViewKnockoutRules:
+		// Drafts, archived jobs, edit history and knockout rules are only visible to the
+		// organization and to admins.
+		if actor.IsAdmin() {
+			return nil
+		}
+		return requireJobRole(actor, resource, orgAnyRole...)
+
+	case ActionUpdateApplicationStatus:
+		return requireJobRole(actor, resource, orgEditors...)
+
+	case ActionViewApplicants:
+		// Applicant data stays inside the organization that posted the job.
+		return requireJobRole(actor, resource, orgAnyRole...)
+
+	case ActionListRecruiterJobs:
+		if actor.IsAdmin() {
+			return nil
+		}
+		ref, ok := resource.(RecruiterRef)
+		if !ok || actor.Role != RoleRecruiter || ref.ID != actor.UserID {
+			return ErrForbidden
+		}
+		return nil
+	}
+
+	return ErrForbidden
+}
+
+func requireRole(actor Actor, role string) error {
+	if actor.Role != role {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// requireOrgRole checks the actor's organization role, and when orgID is given, that the
+// actor belongs to that organization.
+func requireOrgRole(actor Actor, orgID *uuid.UUID, roles ...string) error {
+	m := actor.Membership
+	if m == nil || (orgID != nil && m.OrganizationID != *orgID) {
+		return ErrForbidden
+	}
+	for _, role := range roles {
+		if m.Role == role {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+func requireJobRole(actor Actor, resource interface{}, roles ...string) error {
+	job, ok := resource.(*Job)
+	if !ok || job == nil {
+		return ErrForbidden
+	}
+	return requireOrgRole(actor, &job.OrganizationID, roles...)
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthorize(t *testing.T) {
+	orgID := uuid.New()
+	member := func(role string) *OrganizationMember {
+		return &OrganizationMember{OrganizationID: orgID, Role: role}
+	}
+
+	// Every row is checked against all of these actors. The recruiters with a
+	// membership belong to orgID except outsider, who owns a different organization.
+	actors := map[string]Actor{
+		"seeker":       {UserID: uuid.New(), Role: RoleSeeker},
+		"admin":        {UserID: uuid.New(), Role: RoleAdmin},
+		"owner":        {UserID: uuid.New(), Role: RoleRecruiter, Membership: member(OrgRoleOwner)},
+		"org admin":    {UserID: uuid.New(), Role: RoleRecruiter, Membership: member(OrgRoleAdmin)},
+		"recruiter":    {UserID: uuid.New(), Role: RoleRecruiter, Membership: member(OrgRoleRecruiter)},
+		"viewer":       {UserID: uuid.New(), Role: RoleRecruiter, Membership: member(OrgRoleViewer)},
+		"outsider":     {UserID: uuid.New(), Role: RoleRecruiter, Membership: &OrganizationMember{OrganizationID: uuid.New(), Role: OrgRoleOwner}},
+		"unaffiliated": {UserID: uuid.New(), Role: RoleRecruiter},
+	}
+
+	job := func(Actor) interface{} { return &Job{ID: uuid.New(), OrganizationID: orgID} }
+	none := func(Actor) interface{} { return nil }
+	orgMembers := []string{"owner", "org admin", "recruiter", "viewer"}
+	orgEditorNames := []string{"owner", "org admin", "recruiter"}
+	recruiters := []string{"owner", "org admin", "recruiter", "viewer", "outsider", "unaffiliated"}
+
+	tests := []struct {
+		name     string
+		action   Action
+		resource func(Actor) interface{}
+		allowed  []string
+	}{
+		{name: "apply", action: ActionApplyJob, resource: job, allowed: []string{"seeker"}},
+		{name: "list own applications", action: ActionListOwnApplications, resource: none, allowed: []string{"seeker"}},
+		{name: "create job", action: ActionCreateJob, resource: none, allowed: []string{"owner", "org admin", "recruiter", "outsider"}},
+		{name: "view dashboard", action: ActionViewDashboard, resource: none, allowed: []string{"owner", "org admin", "recruiter", "viewer", "outsider"}},
+		{name: "view organization", action: ActionViewOrganization, resource: none, allowed: []string{"owner", "org admin", "recruiter", "viewer", "outsider"}},
+		{name: "manage organization", action: ActionManageOrganization, resource: none, allowed: []string{"owner", "org admin", "outsider"}},
+		{name: "grant owner", action: ActionGrantOwner, resource: none, allowed: []string{"owner", "outsider"}},
+		{name: "create api key", action: ActionCreateAPIKey, resource: none, allowed: []string{"owner", "org admin", "recruiter", "outsider"}},
+		{
+			name:   "manage own api key",
+			action: ActionManageAPIKey,
+			resource: func(a Actor) interface{} {
+				return &APIKey{OrganizationID: orgID, UserID: a.UserID}
+			},
+			allowed: orgMembers,
+		},
+		{
+			name:   "manage a colleague's api key",
+			action: ActionManageAPIKey,
+			resource: func(Actor) interface{} {
+				return &APIKey{OrganizationID: orgID, UserID: uuid.New()}
+			},
+			allowed: []string{"owner", "org admin"},
+		},
+		{name: "manage api key without a key", action: ActionManageAPIKey, resource: none},
+		{
+			name:     "manage a recruiter member",
+			action:   ActionManageMember,
+			resource: func(Actor) interface{} { return member(OrgRoleRecruiter) },
+			allowed:  []string{"owner", "org admin"},
+		},
+		{
+			name:     "manage an owner member",
+			action:   ActionManageMember,
+			resource: func(Actor) interface{} { return member(OrgRoleOwner) },
+			allowed:  []string{"owner"},
+		},
+		{name: "manage member without a member", action: ActionManageMember, resource: none},
+		{name: "purge job", action: ActionPurgeJob, resource: job, allowed: []string{"admin"}},
+		{name: "update job", action: ActionUpdateJob, resource: job, allowed: append([]string{"admin"}, orgEditorNames...)},
+		{name: "delete job", action: ActionDeleteJob, resource: job, allowed: append([]string{"admin"}, orgEditorNames...)},
+		{name: "update job without a job", action: ActionUpdateJob, resource: none, allowed: []string{"admin"}},
+		{name: "view unpublished job", action: ActionViewUnpublishedJob, resource: job, allowed: append([]string{"admin"}, orgMembers...)},
+		{name: "view job revisions", action: ActionViewJobRevisions, resource: job, allowed: append([]string{"admin"}, orgMembers...)},
+		{name: "view knockout rules", action: ActionViewKnockoutRules, resource: job, allowed: append([]string{"admin"}, orgMembers...)},
+		{name: "update application status", action: ActionUpdateApplicationStatus, resource: job, allowed: orgEditorNames},
+		{name: "view applicants", action: ActionViewApplicants, resource: job, allowed: orgMembers},
+		{
+			name:     "list own recruiter jobs",
+			action:   ActionListRecruiterJobs,
+			resource: func(a Actor) interface{} { return RecruiterRef{ID: a.UserID} },
+			allowed:  append([]string{"admin"}, recruiters...),
+		},
+		{
+			name:     "list another recruiter's jobs",
+			action:   ActionListRecruiterJobs,
+			resource: func(Actor) interface{} { return RecruiterRef{ID: uuid.New()} },
+			allowed:  []string{"admin"},
+		},
+		{name: "unknown action", action: Action("job:teleport"), resource: job},
+	}
+
+	names := make([]string, 0, len(actors))
+	for name := range actors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, tt := range tests {
+		allowed := make(map[string]bool, len(tt.allowed))
+		for _, name := range tt.allowed {
+			if _, ok := actors[name]; !ok {
+				t.Fatalf("%s: unknown actor %q", tt.name, name)
+			}
+			allowed[name] = true
+		}
+		for _, name := range names {
+			actor := actors[name]
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				err := Authorize(actor, tt.action, tt.resource(actor))
+				switch {
+				case allowed[name] && err != nil:
+					t.Fatalf("denied: %v", err)
+				case !allowed[name] && !errors.Is(err, ErrForbidden):
+					t.Fatalf("err = %v, want ErrForbidden", err)
+				}
+			})
+		}
+	}
+}
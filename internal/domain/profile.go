@@ -57,29 +57,32 @@ type SeekerProfile struct {
 }
 
 type CompanyProfile struct {
-	ID          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	UserID      uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user_id"`
-	User        *User          `gorm:"foreignKey:UserID;references:ID" json:"-"`
-	CompanyName string         `json:"company_name"`
-	Website     string         `json:"website"`
-	Phone       string         `json:"phone"`
-	Location    string         `json:"location"`
-	Description string         `gorm:"type:text" json:"description"`
-	LogoURL     string         `json:"logo_url"`
+	ID             uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	UserID         uuid.UUID      `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user_id"`
+	User           *User          `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	OrganizationID *uuid.UUID     `gorm:"type:uuid;uniqueIndex;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"organization_id"`
+	Organization   *Organization  `gorm:"foreignKey:OrganizationID;references:ID" json:"-"`
+	CompanyName    string         `json:"company_name"`
+	Website        string         `json:"website"`
+	Phone          string         `json:"phone"`
+	Location       string         `json:"location"`
+	Description    string         `gorm:"type:text" json:"description"`
+	LogoURL        string         `json:"logo_url"`
 }
 
 type ProfileRepository interface {
 	GetSeekerProfile(ctx context.Context, userID uuid.UUID) (*SeekerProfile, error)
 	UpdateSeekerProfile(ctx context.Context, profile *SeekerProfile) error
-	GetCompanyProfile(ctx context.Context, userID uuid.UUID) (*CompanyProfile, error)
+	GetCompanyProfile(ctx context.Context, orgID uuid.UUID) (*CompanyProfile, error)
+	// UpdateCompanyProfile creates or replaces the profile of profile.OrganizationID.
 	UpdateCompanyProfile(ctx context.Context, profile *CompanyProfile) error
 }
 
 type ProfileUsecase interface {
-	GetProfile(ctx context.Context, userID uuid.UUID, role string) (interface{}, error)
+	GetProfile(ctx context.Context, actor Actor) (interface{}, error)
 	UpdateSeekerProfile(ctx context.Context, userID uuid.UUID, profile *SeekerProfile) error
-	UpdateCompanyProfile(ctx context.Context, userID uuid.UUID, profile *CompanyProfile) error
+	UpdateCompanyProfile(ctx context.Context, actor Actor, profile *CompanyProfile) error
 }
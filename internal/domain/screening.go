@@ -0,0 +1,298 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of screening question.
+const (
+	QuestionYesNo          = "yes_no"
+	QuestionSingleChoice   = "single_choice"
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionNumber         = "number"
+	QuestionText           = "text"
+)
+
+// What happens to an application whose answer fails a knockout rule.
+const (
+	KnockoutReject = "reject"
+	KnockoutFlag   = "flag"
+)
+
+const (
+	maxScreeningQuestions = 20
+	maxTextAnswerLength   = 2000
+)
+
+// ScreeningQuestion is asked of every candidate applying to a job. IDs are kept across
+// edits so answers keep pointing at the right question.
+type ScreeningQuestion struct {
+	ID       uuid.UUID     `json:"id"`
+	Type     string        `json:"type"`
+	Prompt   string        `json:"prompt"`
+	Required bool          `json:"required"`
+	Options  []string      `json:"options,omitempty"`
+	Knockout *KnockoutRule `json:"knockout,omitempty"`
+}
+
+// KnockoutRule is a hard requirement on a required question's answer; only the field
+// matching the question type is used. Equals applies to yes/no questions, OneOf lists
+// the acceptable options of a single choice, AllOf the options a multiple choice must
+// include, and Min and Max bound a number. Free text cannot be knocked out.
+type KnockoutRule struct {
+	Action string   `json:"action"`
+	Equals *bool    `json:"equals,omitempty"`
+	OneOf  []string `json:"one_of,omitempty"`
+	AllOf  []string `json:"all_of,omitempty"`
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+}
+
+// ScreeningAnswer answers one question; only the field matching the question type is set.
+type ScreeningAnswer struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Boolean    *bool     `json:"boolean,omitempty"`
+	Choices    []string  `json:"choices,omitempty"`
+	Number     *float64  `json:"number,omitempty"`
+	Text       string    `json:"text,omitempty"`
+}
+
+// ScreeningResult is the outcome of checking answers against knockout rules: Reject
+// and Flag say whether any failed rule had that action.
+type ScreeningResult struct {
+	FailedQuestionIDs []uuid.UUID
+	Reject            bool
+	Flag              bool
+}
+
+// PrepareScreeningQuestions validates the questions a recruiter wrote and gives new
+// ones an ID.
+func PrepareScreeningQuestions(questions []ScreeningQuestion) error {
+	if len(questions) > maxScreeningQuestions {
+		return &ValidationError{Message: fmt.Sprintf("a job can have at most %d screening questions", maxScreeningQuestions)}
+	}
+
+	seen := make(map[uuid.UUID]bool, len(questions))
+	for i := range questions {
+		q := &questions[i]
+		if q.ID == uuid.Nil {
+			q.ID = uuid.New()
+		}
+		if seen[q.ID] {
+			return &ValidationError{Message: fmt.Sprintf("question %d repeats the ID of another question", i+1)}
+		}
+		seen[q.ID] = true
+
+		if strings.TrimSpace(q.Prompt) == "" {
+			return &ValidationError{Message: fmt.Sprintf("question %d needs a prompt", i+1)}
+		}
+		if err := validateQuestionOptions(q); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("question %d: %s", i+1, err)}
+		}
+		if q.Knockout != nil {
+			if err := validateKnockout(q); err != nil {
+				return &ValidationError{Message: fmt.Sprintf("question %d: %s", i+1, err)}
+			}
+		}
+	}
+	return nil
+}
+
+func validateQuestionOptions(q *ScreeningQuestion) error {
+	switch q.Type {
+	case QuestionSingleChoice, QuestionMultipleChoice:
+		if len(q.Options) < 2 {
+			return fmt.Errorf("choice questions need at least two options")
+		}
+		seen := make(map[string]bool, len(q.Options))
+		for _, option := range q.Options {
+			if strings.TrimSpace(option) == "" || seen[option] {
+				return fmt.Errorf("options must be unique and not empty")
+			}
+			seen[option] = true
+		}
+	case QuestionYesNo, QuestionNumber, QuestionText:
+		if len(q.Options) > 0 {
+			return fmt.Errorf("only choice questions have options")
+		}
+	default:
+		return fmt.Errorf("unknown question type %q", q.Type)
+	}
+	return nil
+}
+
+func validateKnockout(q *ScreeningQuestion) error {
+	k := q.Knockout
+	if k.Action != KnockoutReject && k.Action != KnockoutFlag {
+		return fmt.Errorf("knockout action must be reject or flag")
+	}
+	if !q.Required {
+		return fmt.Errorf("only required questions can have a knockout rule")
+	}
+
+	switch q.Type {
+	case QuestionYesNo:
+		if k.Equals == nil {
+			return fmt.Errorf("a yes/no knockout needs equals")
+		}
+	case QuestionSingleChoice:
+		if len(k.OneOf) == 0 || !isSubset(k.OneOf, q.Options) {
+			return fmt.Errorf("a single choice knockout needs one_of taken from the options")
+		}
+	case QuestionMultipleChoice:
+		if len(k.AllOf) == 0 || !isSubset(k.AllOf, q.Options) {
+			return fmt.Errorf("a multiple choice knockout needs all_of taken from the options")
+		}
+	case QuestionNumber:
+		if k.Min == nil && k.Max == nil {
+			return fmt.Errorf("a number knockout needs min or max")
+		}
+		if k.Min != nil && k.Max != nil && *k.Min > *k.Max {
+			return fmt.Errorf("knockout min cannot be greater than max")
+		}
+	case QuestionText:
+		return fmt.Errorf("free text answers cannot be knocked out")
+	}
+	return nil
+}
+
+// WithoutKnockouts copies the questions without their knockout rules, which candidates
+// must not see.
+func WithoutKnockouts(questions []ScreeningQuestion) []ScreeningQuestion {
+	if questions == nil {
+		return nil
+	}
+	public := make([]ScreeningQuestion, len(questions))
+	for i, q := range questions {
+		q.Knockout = nil
+		public[i] = q
+	}
+	return public
+}
+
+// EvaluateScreening validates a candidate's answers against the questions and checks
+// the knockout rules. Unknown, duplicate, malformed or missing required answers are a
+// ValidationError.
+func EvaluateScreening(questions []ScreeningQuestion, answers []ScreeningAnswer) (ScreeningResult, error) {
+	var result ScreeningResult
+
+	byID := make(map[uuid.UUID]ScreeningAnswer, len(answers))
+	for _, answer := range answers {
+		if _, ok := byID[answer.QuestionID]; ok {
+			return result, &ValidationError{Message: "each question can only be answered once"}
+		}
+		byID[answer.QuestionID] = answer
+	}
+
+	known := make(map[uuid.UUID]bool, len(questions))
+	for _, q := range questions {
+		known[q.ID] = true
+		answer, answered := byID[q.ID]
+		if answered {
+			if err := validateAnswer(q, answer); err != nil {
+				return result, &ValidationError{Message: fmt.Sprintf("%q: %s", q.Prompt, err)}
+			}
+		} else if q.Required {
+			return result, &ValidationError{Message: fmt.Sprintf("%q must be answered", q.Prompt)}
+		}
+
+		if q.Knockout != nil && !passesKnockout(q, answer) {
+			result.FailedQuestionIDs = append(result.FailedQuestionIDs, q.ID)
+			if q.Knockout.Action == KnockoutReject {
+				result.Reject = true
+			} else {
+				result.Flag = true
+			}
+		}
+	}
+	for id := range byID {
+		if !known[id] {
+			return result, &ValidationError{Message: "answers must refer to the job's screening questions"}
+		}
+	}
+	return result, nil
+}
+
+func validateAnswer(q ScreeningQuestion, a ScreeningAnswer) error {
+	switch q.Type {
+	case QuestionYesNo:
+		if a.Boolean == nil || a.Choices != nil || a.Number != nil || a.Text != "" {
+			return fmt.Errorf("answer with boolean only")
+		}
+	case QuestionSingleChoice:
+		if len(a.Choices) != 1 || a.Boolean != nil || a.Number != nil || a.Text != "" {
+			return fmt.Errorf("pick exactly one of the options in choices")
+		}
+		if !isSubset(a.Choices, q.Options) {
+			return fmt.Errorf("choose from the listed options")
+		}
+	case QuestionMultipleChoice:
+		if len(a.Choices) == 0 || a.Boolean != nil || a.Number != nil || a.Text != "" {
+			return fmt.Errorf("pick at least one of the options in choices")
+		}
+		seen := make(map[string]bool, len(a.Choices))
+		for _, choice := range a.Choices {
+			if seen[choice] {
+				return fmt.Errorf("each option can only be picked once")
+			}
+			seen[choice] = true
+		}
+		if !isSubset(a.Choices, q.Options) {
+			return fmt.Errorf("choose from the listed options")
+		}
+	case QuestionNumber:
+		if a.Number == nil || math.IsNaN(*a.Number) || math.IsInf(*a.Number, 0) || a.Boolean != nil || a.Choices != nil || a.Text != "" {
+			return fmt.Errorf("answer with a number only")
+		}
+	case QuestionText:
+		if strings.TrimSpace(a.Text) == "" || a.Boolean != nil || a.Choices != nil || a.Number != nil {
+			return fmt.Errorf("answer with text only")
+		}
+		if utf8.RuneCountInString(a.Text) > maxTextAnswerLength {
+			return fmt.Errorf("answers are limited to %d characters", maxTextAnswerLength)
+		}
+	}
+	return nil
+}
+
+// passesKnockout checks an already validated answer. Knockout questions are required,
+// so the answer is always present.
+func passesKnockout(q ScreeningQuestion, a ScreeningAnswer) bool {
+	k := q.Knockout
+	switch q.Type {
+	case QuestionYesNo:
+		return a.Boolean != nil && *a.Boolean == *k.Equals
+	case QuestionSingleChoice:
+		return isSubset(a.Choices, k.OneOf)
+	case QuestionMultipleChoice:
+		return isSubset(k.AllOf, a.Choices)
+	case QuestionNumber:
+		if a.Number == nil {
+			return false
+		}
+		return (k.Min == nil || *a.Number >= *k.Min) && (k.Max == nil || *a.Number <= *k.Max)
+	}
+	return true
+}
+
+// isSubset reports whether every value is in set.
+func isSubset(values, set []string) bool {
+	for _, v := range values {
+		found := false
+		for _, s := range set {
+			if v == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
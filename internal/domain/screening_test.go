@@ -0,0 +1,356 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPrepareScreeningQuestions(t *testing.T) {
+	yes := true
+	low, high := 1.0, 5.0
+	question := func(mutate func(*ScreeningQuestion)) ScreeningQuestion {
+		q := ScreeningQuestion{Type: QuestionYesNo, Prompt: "Can you work on site?", Required: true}
+		mutate(&q)
+		return q
+	}
+	choice := func(kind string, options ...string) func(*ScreeningQuestion) {
+		return func(q *ScreeningQuestion) { q.Type, q.Options = kind, options }
+	}
+	repeatedID := uuid.New()
+
+	tests := []struct {
+		name      string
+		questions []ScreeningQuestion
+		wantErr   string
+	}{
+		{name: "no questions"},
+		{
+			name: "every type",
+			questions: []ScreeningQuestion{
+				question(func(q *ScreeningQuestion) { q.Knockout = &KnockoutRule{Action: KnockoutReject, Equals: &yes} }),
+				question(choice(QuestionSingleChoice, "Remote", "Hybrid")),
+				question(choice(QuestionMultipleChoice, "Go", "Rust", "Java")),
+				question(func(q *ScreeningQuestion) { q.Type = QuestionNumber }),
+				question(func(q *ScreeningQuestion) { q.Type, q.Required = QuestionText, false }),
+			},
+		},
+		{
+			name:      "too many questions",
+			questions: make([]ScreeningQuestion, maxScreeningQuestions+1),
+			wantErr:   "at most",
+		},
+		{
+			name:      "blank prompt",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) { q.Prompt = "  " })},
+			wantErr:   "needs a prompt",
+		},
+		{
+			name: "repeated ID",
+			questions: []ScreeningQuestion{
+				question(func(q *ScreeningQuestion) { q.ID = repeatedID }),
+				question(func(q *ScreeningQuestion) { q.ID = repeatedID }),
+			},
+			wantErr: "repeats the ID",
+		},
+		{
+			name:      "unknown type",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) { q.Type = "essay" })},
+			wantErr:   "unknown question type",
+		},
+		{
+			name:      "choice with one option",
+			questions: []ScreeningQuestion{question(choice(QuestionSingleChoice, "Only"))},
+			wantErr:   "at least two options",
+		},
+		{
+			name:      "duplicate options",
+			questions: []ScreeningQuestion{question(choice(QuestionMultipleChoice, "Go", "Go"))},
+			wantErr:   "unique and not empty",
+		},
+		{
+			name:      "empty option",
+			questions: []ScreeningQuestion{question(choice(QuestionSingleChoice, "Go", " "))},
+			wantErr:   "unique and not empty",
+		},
+		{
+			name:      "options on a yes/no question",
+			questions: []ScreeningQuestion{question(choice(QuestionYesNo, "Yes", "No"))},
+			wantErr:   "only choice questions",
+		},
+		{
+			name: "unknown knockout action",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				q.Knockout = &KnockoutRule{Action: "drop", Equals: &yes}
+			})},
+			wantErr: "reject or flag",
+		},
+		{
+			name: "knockout on an optional question",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				q.Required = false
+				q.Knockout = &KnockoutRule{Action: KnockoutReject, Equals: &yes}
+			})},
+			wantErr: "only required questions",
+		},
+		{
+			name: "yes/no knockout without equals",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				q.Knockout = &KnockoutRule{Action: KnockoutFlag}
+			})},
+			wantErr: "needs equals",
+		},
+		{
+			name: "single choice knockout outside the options",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				choice(QuestionSingleChoice, "Remote", "Hybrid")(q)
+				q.Knockout = &KnockoutRule{Action: KnockoutReject, OneOf: []string{"On site"}}
+			})},
+			wantErr: "one_of taken from the options",
+		},
+		{
+			name: "multiple choice knockout without all_of",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				choice(QuestionMultipleChoice, "Go", "Rust")(q)
+				q.Knockout = &KnockoutRule{Action: KnockoutReject}
+			})},
+			wantErr: "all_of taken from the options",
+		},
+		{
+			name: "number knockout without bounds",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				q.Type = QuestionNumber
+				q.Knockout = &KnockoutRule{Action: KnockoutReject}
+			})},
+			wantErr: "needs min or max",
+		},
+		{
+			name: "number knockout with min above max",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				q.Type = QuestionNumber
+				q.Knockout = &KnockoutRule{Action: KnockoutReject, Min: &high, Max: &low}
+			})},
+			wantErr: "min cannot be greater than max",
+		},
+		{
+			name: "text knockout",
+			questions: []ScreeningQuestion{question(func(q *ScreeningQuestion) {
+				q.Type = QuestionText
+				q.Knockout = &KnockoutRule{Action: KnockoutFlag}
+			})},
+			wantErr: "cannot be knocked out",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PrepareScreeningQuestions(tt.questions)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for i, q := range tt.questions {
+					if q.ID == uuid.Nil {
+						t.Fatalf("question %d was not given an ID", i+1)
+					}
+				}
+				return
+			}
+			var validation *ValidationError
+			if !errors.As(err, &validation) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want a validation error mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrepareScreeningQuestionsKeepsIDs(t *testing.T) {
+	id := uuid.New()
+	questions := []ScreeningQuestion{{ID: id, Type: QuestionYesNo, Prompt: "Kept?"}}
+	if err := PrepareScreeningQuestions(questions); err != nil {
+		t.Fatal(err)
+	}
+	if questions[0].ID != id {
+		t.Fatalf("ID changed from %s to %s", id, questions[0].ID)
+	}
+}
+
+func TestEvaluateScreening(t *testing.T) {
+	yes, no := true, false
+	three, ten, twelve := 3.0, 10.0, 12.0
+	permit := ScreeningQuestion{ID: uuid.New(), Type: QuestionYesNo, Prompt: "Work permit?", Required: true,
+		Knockout: &KnockoutRule{Action: KnockoutReject, Equals: &yes}}
+	location := ScreeningQuestion{ID: uuid.New(), Type: QuestionSingleChoice, Prompt: "Where?", Required: true,
+		Options: []string{"Remote", "Hybrid", "On site"}, Knockout: &KnockoutRule{Action: KnockoutFlag, OneOf: []string{"Hybrid", "On site"}}}
+	stack := ScreeningQuestion{ID: uuid.New(), Type: QuestionMultipleChoice, Prompt: "Stack?", Required: true,
+		Options: []string{"Go", "SQL", "Rust"}, Knockout: &KnockoutRule{Action: KnockoutReject, AllOf: []string{"Go", "SQL"}}}
+	years := ScreeningQuestion{ID: uuid.New(), Type: QuestionNumber, Prompt: "Years?", Required: true,
+		Knockout: &KnockoutRule{Action: KnockoutFlag, Min: &three, Max: &ten}}
+	notes := ScreeningQuestion{ID: uuid.New(), Type: QuestionText, Prompt: "Anything else?"}
+	questions := []ScreeningQuestion{permit, location, stack, years, notes}
+
+	passing := func() []ScreeningAnswer {
+		return []ScreeningAnswer{
+			{QuestionID: permit.ID, Boolean: &yes},
+			{QuestionID: location.ID, Choices: []string{"Hybrid"}},
+			{QuestionID: stack.ID, Choices: []string{"SQL", "Go", "Rust"}},
+			{QuestionID: years.ID, Number: &three},
+		}
+	}
+	with := func(change func([]ScreeningAnswer) []ScreeningAnswer) []ScreeningAnswer {
+		return change(passing())
+	}
+
+	tests := []struct {
+		name       string
+		answers    []ScreeningAnswer
+		wantErr    string
+		wantFailed []uuid.UUID
+		wantReject bool
+		wantFlag   bool
+	}{
+		{name: "all knockouts passed", answers: passing()},
+		{
+			name: "optional text answered",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer {
+				return append(a, ScreeningAnswer{QuestionID: notes.ID, Text: "Available in March"})
+			}),
+		},
+		{
+			name:       "yes/no knockout rejects",
+			answers:    with(func(a []ScreeningAnswer) []ScreeningAnswer { a[0].Boolean = &no; return a }),
+			wantFailed: []uuid.UUID{permit.ID},
+			wantReject: true,
+		},
+		{
+			name:       "single choice outside one_of flags",
+			answers:    with(func(a []ScreeningAnswer) []ScreeningAnswer { a[1].Choices = []string{"Remote"}; return a }),
+			wantFailed: []uuid.UUID{location.ID},
+			wantFlag:   true,
+		},
+		{
+			name:       "multiple choice missing part of all_of rejects",
+			answers:    with(func(a []ScreeningAnswer) []ScreeningAnswer { a[2].Choices = []string{"Go", "Rust"}; return a }),
+			wantFailed: []uuid.UUID{stack.ID},
+			wantReject: true,
+		},
+		{
+			name:       "number above max flags",
+			answers:    with(func(a []ScreeningAnswer) []ScreeningAnswer { a[3].Number = &twelve; return a }),
+			wantFailed: []uuid.UUID{years.ID},
+			wantFlag:   true,
+		},
+		{
+			name: "reject and flag together",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer {
+				a[0].Boolean = &no
+				a[3].Number = &twelve
+				return a
+			}),
+			wantFailed: []uuid.UUID{permit.ID, years.ID},
+			wantReject: true,
+			wantFlag:   true,
+		},
+		{
+			name:    "missing required answer",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer { return a[1:] }),
+			wantErr: "must be answered",
+		},
+		{
+			name: "answer to an unknown question",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer {
+				return append(a, ScreeningAnswer{QuestionID: uuid.New(), Boolean: &yes})
+			}),
+			wantErr: "refer to the job's screening questions",
+		},
+		{
+			name:    "question answered twice",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer { return append(a, a[0]) }),
+			wantErr: "only be answered once",
+		},
+		{
+			name:    "single choice with two picks",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer { a[1].Choices = []string{"Hybrid", "Remote"}; return a }),
+			wantErr: "exactly one",
+		},
+		{
+			name:    "choice not among the options",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer { a[1].Choices = []string{"Mars"}; return a }),
+			wantErr: "listed options",
+		},
+		{
+			name:    "multiple choice picking an option twice",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer { a[2].Choices = []string{"Go", "Go", "SQL"}; return a }),
+			wantErr: "picked once",
+		},
+		{
+			name: "wrong answer field for the type",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer {
+				a[0] = ScreeningAnswer{QuestionID: permit.ID, Text: "yes"}
+				return a
+			}),
+			wantErr: "boolean only",
+		},
+		{
+			name: "blank text answer",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer {
+				return append(a, ScreeningAnswer{QuestionID: notes.ID, Text: "   "})
+			}),
+			wantErr: "text only",
+		},
+		{
+			name: "overlong text answer",
+			answers: with(func(a []ScreeningAnswer) []ScreeningAnswer {
+				return append(a, ScreeningAnswer{QuestionID: notes.ID, Text: strings.Repeat("a", maxTextAnswerLength+1)})
+			}),
+			wantErr: "limited to",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateScreening(questions, tt.answers)
+			if tt.wantErr != "" {
+				var validation *ValidationError
+				if !errors.As(err, &validation) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want a validation error mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.FailedQuestionIDs, tt.wantFailed) || result.Reject != tt.wantReject || result.Flag != tt.wantFlag {
+				t.Fatalf("result = %+v, want failed %v, reject %v, flag %v", result, tt.wantFailed, tt.wantReject, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestWithoutKnockouts(t *testing.T) {
+	yes := true
+	questions := []ScreeningQuestion{
+		{ID: uuid.New(), Type: QuestionYesNo, Prompt: "Permit?", Required: true, Knockout: &KnockoutRule{Action: KnockoutReject, Equals: &yes}},
+		{ID: uuid.New(), Type: QuestionText, Prompt: "Notes?"},
+	}
+
+	public := WithoutKnockouts(questions)
+	if len(public) != len(questions) {
+		t.Fatalf("got %d questions, want %d", len(public), len(questions))
+	}
+	for i, q := range public {
+		if q.Knockout != nil {
+			t.Fatalf("question %d still has its knockout rule", i+1)
+		}
+		if q.ID != questions[i].ID || q.Prompt != questions[i].Prompt {
+			t.Fatalf("question %d = %+v, want a copy of %+v", i+1, q, questions[i])
+		}
+	}
+	if questions[0].Knockout == nil || questions[0].Knockout.Equals != &yes {
+		t.Fatal("WithoutKnockouts changed the questions it was given")
+	}
+	if WithoutKnockouts(nil) != nil {
+		t.Fatal("WithoutKnockouts(nil) should stay nil")
+	}
+}
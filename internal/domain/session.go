@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a signed-in device. ImpersonatorID is set when an admin opened the
+// session to act as the user.
+type Session struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user_id"`
+	User             *User      `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	RefreshTokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	UserAgent        string     `json:"user_agent"`
+	IP               string     `json:"ip"`
+	LastSeenAt       *time.Time `json:"last_seen_at"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	ImpersonatorID   *uuid.UUID `gorm:"type:uuid" json:"impersonator_id,omitempty"`
+
+	// Current marks the session the listing request was made with.
+	Current bool `gorm:"-" json:"current"`
+}
+
+// RotatedRefreshToken remembers a refresh token that has been replaced. Presenting one
+// again means the token was copied, so the whole session is revoked.
+type RotatedRefreshToken struct {
+	Hash      string    `gorm:"primaryKey" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	SessionID uuid.UUID `gorm:"type:uuid;not null;index" json:"session_id"`
+	Session   *Session  `gorm:"foreignKey:SessionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+// IsActive reports whether the session can still be used to authenticate requests.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+type AuthTokens struct {
+	AccessToken  string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *Session) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Session, error)
+	GetByRefreshTokenHash(ctx context.Context, hash string) (*Session, error)
+	// GetByRotatedRefreshTokenHash finds the session a replaced refresh token belonged to.
+	GetByRotatedRefreshTokenHash(ctx context.Context, hash string) (*Session, error)
+	RotateRefreshToken(ctx context.Context, id uuid.UUID, oldHash, newHash string, expiresAt time.Time) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+	RevokeAllByUserID(ctx context.Context, userID uuid.UUID) error
+	// RevokeForUser revokes a session only if it belongs to userID, returning ErrNotFound otherwise.
+	RevokeForUser(ctx context.Context, id, userID uuid.UUID) error
+	ListActiveByUserID(ctx context.Context, userID uuid.UUID, now time.Time) ([]Session, error)
+	Touch(ctx context.Context, id uuid.UUID, seenAt time.Time) error
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user_id"`
+	User      *User      `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	CodeHash  string     `gorm:"not null;index" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// LoginResult carries either a full token pair or, when the account has two-factor
+// authentication enabled, a short-lived challenge token to exchange for one. A social
+// callback that completed an account link returns the new identity instead.
+type LoginResult struct {
+	Tokens         *AuthTokens
+	ChallengeToken string
+	LinkedIdentity *UserIdentity
+}
+
+type RecoveryCodeRepository interface {
+	Replace(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	Consume(ctx context.Context, userID uuid.UUID, codeHash string) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
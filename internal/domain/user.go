@@ -8,27 +8,77 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleSeeker    = "SEEKER"
+	RoleRecruiter = "RECRUITER"
+	RoleAdmin     = "ADMIN"
+)
+
 type User struct {
-	ID             uuid.UUID       `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	CreatedAt      time.Time       `json:"created_at"`
-	UpdatedAt      time.Time       `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt  `gorm:"index" json:"deleted_at"`
-	Email          string          `gorm:"uniqueIndex;not null" json:"email"`
-	Password       string          `json:"-"`
-	Role           string          `gorm:"default:'SEEKER'" json:"role"`    // RECRUITER, SEEKER
-	Provider       string          `gorm:"default:'local'" json:"provider"` // local, google
-	SeekerProfile  *SeekerProfile  `gorm:"foreignKey:UserID" json:"seeker_profile,omitempty"`
-	CompanyProfile *CompanyProfile `gorm:"foreignKey:UserID" json:"company_profile,omitempty"`
+	ID                 uuid.UUID       `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt  `gorm:"index" json:"deleted_at"`
+	Email              string          `gorm:"uniqueIndex;not null" json:"email"`
+	Password           string          `json:"-"`
+	Role               string          `gorm:"default:'SEEKER'" json:"role"`    // RECRUITER, SEEKER, ADMIN
+	Provider           string          `gorm:"default:'local'" json:"provider"` // local, or the name of the OIDC provider used to sign up
+	EmailVerifiedAt    *time.Time      `json:"email_verified_at"`
+	TOTPSecret         string          `json:"-"`
+	TOTPLastStep       int64           `json:"-"`
+	TwoFactorEnabledAt *time.Time      `json:"two_factor_enabled_at"`
+	SuspendedAt        *time.Time      `json:"suspended_at"`
+	SeekerProfile      *SeekerProfile  `gorm:"foreignKey:UserID" json:"seeker_profile,omitempty"`
+	CompanyProfile     *CompanyProfile `gorm:"foreignKey:UserID" json:"company_profile,omitempty"`
+}
+
+// IsEmailVerified reports whether the user has proven ownership of their email address.
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsTwoFactorEnabled reports whether login requires a TOTP or recovery code.
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+// IsSuspended reports whether an admin has blocked the account from signing in.
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
 }
 
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+	SetTwoFactor(ctx context.Context, id uuid.UUID, secret string, enabledAt *time.Time) error
+	AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) error
+	List(ctx context.Context, filter UserFilter) ([]User, int64, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) error
+	SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time) error
 }
 
 type AuthUsecase interface {
 	Register(ctx context.Context, email, password, role string) error
-	Login(ctx context.Context, email, password string) (string, error)
-	GoogleLogin(ctx context.Context, code string) (string, error)
+	Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error)
+	OAuthLoginURL(ctx context.Context, provider string) (*OAuthRedirect, error)
+	OAuthLogin(ctx context.Context, provider, code, state string, client ClientInfo) (*LoginResult, error)
+	StartIdentityLink(ctx context.Context, userID uuid.UUID, provider string) (*OAuthRedirect, error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error)
+	UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error
+	VerifyTwoFactor(ctx context.Context, challengeToken, code string, client ClientInfo) (*AuthTokens, error)
+	Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error)
+	Logout(ctx context.Context, userID, sessionID uuid.UUID) error
+	ValidateSession(ctx context.Context, sessionID uuid.UUID) error
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID uuid.UUID) error
+	EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error
 }
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserToken is a hashed, single-use token mailed to a user to prove control of their address.
+type UserToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user_id"`
+	User      *User      `gorm:"foreignKey:UserID;references:ID" json:"-"`
+	Purpose   string     `gorm:"not null;index" json:"purpose"` // PASSWORD_RESET, EMAIL_VERIFICATION
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+const (
+	TokenPurposePasswordReset     = "PASSWORD_RESET"
+	TokenPurposeEmailVerification = "EMAIL_VERIFICATION"
+)
+
+// IsUsable reports whether the token has neither been consumed nor expired.
+func (t *UserToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *UserToken) error
+	GetByHash(ctx context.Context, purpose, hash string) (*UserToken, error)
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+	InvalidateByUserID(ctx context.Context, userID uuid.UUID, purpose string) error
+	CountCreatedSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int64, error)
+}
@@ -20,17 +20,22 @@ func (r *applicationRepository) Create(ctx context.Context, app *domain.Applicat
 	return r.db.WithContext(ctx).Create(app).Error
 }
 
-func (r *applicationRepository) GetByJobID(ctx context.Context, jobID uuid.UUID) ([]domain.Application, error) {
+func (r *applicationRepository) GetByJobID(ctx context.Context, jobID uuid.UUID, sort string) ([]domain.Application, error) {
 	var apps []domain.Application
-	err := r.db.WithContext(ctx).Preload("Seeker").Preload("Seeker.SeekerProfile").Where("job_id = ?", jobID).Find(&apps).Error
+	query := r.db.WithContext(ctx).Preload("Seeker").Preload("Seeker.SeekerProfile").Where("job_id = ?", jobID)
+	if sort == domain.ApplicantSortMatch {
+		query = query.Order("match_score DESC NULLS LAST")
+	}
+	err := query.Order("created_at DESC").Find(&apps).Error
 	return apps, err
 }
 
 func (r *applicationRepository) GetBySeekerID(ctx context.Context, seekerID uuid.UUID) ([]domain.Application, error) {
 	var apps []domain.Application
 	err := r.db.WithContext(ctx).
+		// Seekers keep seeing jobs that were deleted after they applied.
 		Preload("Job", func(db *gorm.DB) *gorm.DB {
-			return db.Select("id, title, category, job_type, recruiter_id")
+			return db.Unscoped().Select("id, title, category, job_type, status, deleted_at, recruiter_id, organization_id")
 		}).
 		Preload("Job.Company").
 		Where("seeker_id = ?", seekerID).
@@ -51,13 +56,13 @@ func (r *applicationRepository) UpdateStatus(ctx context.Context, id uuid.UUID,
 	return r.db.WithContext(ctx).Model(&domain.Application{}).Where("id = ?", id).Update("status", status).Error
 }
 
-func (r *applicationRepository) GetDashboardStats(ctx context.Context, recruiterID uuid.UUID) (*domain.DashboardStats, error) {
+func (r *applicationRepository) GetDashboardStats(ctx context.Context, orgID uuid.UUID) (*domain.DashboardStats, error) {
 	stats := &domain.DashboardStats{
 		StatusDistribution: make(map[string]int),
 	}
 
 	var totalJobs int64
-	if err := r.db.Model(&domain.Job{}).Where("recruiter_id = ?", recruiterID).Count(&totalJobs).Error; err != nil {
+	if err := r.db.Model(&domain.Job{}).Where("organization_id = ?", orgID).Count(&totalJobs).Error; err != nil {
 		return nil, err
 	}
 	stats.TotalJobs = int(totalJobs)
@@ -65,7 +70,7 @@ func (r *applicationRepository) GetDashboardStats(ctx context.Context, recruiter
 	var totalApplicants int64
 	err := r.db.Model(&domain.Application{}).
 		Joins("JOIN jobs ON jobs.id = applications.job_id").
-		Where("jobs.recruiter_id = ?", recruiterID).
+		Where("jobs.organization_id = ?", orgID).
 		Count(&totalApplicants).Error
 	if err != nil {
 		return nil, err
@@ -75,7 +80,7 @@ func (r *applicationRepository) GetDashboardStats(ctx context.Context, recruiter
 	rows, err := r.db.Model(&domain.Application{}).
 		Select("applications.status, count(*) as count").
 		Joins("JOIN jobs ON jobs.id = applications.job_id").
-		Where("jobs.recruiter_id = ?", recruiterID).
+		Where("jobs.organization_id = ?", orgID).
 		Group("applications.status").
 		Rows()
 	if err != nil {
@@ -95,7 +100,7 @@ func (r *applicationRepository) GetDashboardStats(ctx context.Context, recruiter
 	trendRows, err := r.db.Model(&domain.Application{}).
 		Select("to_char(applications.created_at, 'YYYY-MM-DD') as date, count(*) as count").
 		Joins("JOIN jobs ON jobs.id = applications.job_id").
-		Where("jobs.recruiter_id = ?", recruiterID).
+		Where("jobs.organization_id = ?", orgID).
 		Group("date").
 		Order("date ASC").
 		Rows()
@@ -115,11 +120,11 @@ func (r *applicationRepository) GetDashboardStats(ctx context.Context, recruiter
 
 	var recentApps []domain.Application
 	err = r.db.Preload("Job", func(db *gorm.DB) *gorm.DB {
-		return db.Select("id, title, job_type, recruiter_id")
+		return db.Select("id, title, job_type, recruiter_id, organization_id")
 	}).Preload("Job.Company").
 		Preload("Seeker").Preload("Seeker.SeekerProfile").
 		Joins("JOIN jobs ON jobs.id = applications.job_id").
-		Where("jobs.recruiter_id = ?", recruiterID).
+		Where("jobs.organization_id = ?", orgID).
 		Order("applications.created_at DESC").
 		Limit(5).
 		Find(&recentApps).Error
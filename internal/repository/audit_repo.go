@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"be-job-portal/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository stores audit events. It only inserts; the table also rejects
+// updates and deletes at the database level.
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event *domain.AuditEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *auditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AuditEvent{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ? OR impersonator_id = ?", *filter.ActorID, *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []domain.AuditEvent
+	offset := (filter.Page - 1) * filter.Limit
+	err := query.Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(offset).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, totalCount, nil
+}
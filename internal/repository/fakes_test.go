@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingDB plays the part of Postgres for tests that need a repository method to
+// run end to end: it records every statement and answers queries through rows.
+type recordingDB struct {
+	mu         sync.Mutex
+	statements []string
+	// rows returns the columns and rows for a query; nil columns mean no rows.
+	rows func(query string) ([]string, [][]driver.Value)
+}
+
+func (d *recordingDB) open(t *testing.T) *gorm.DB {
+	t.Helper()
+	conn := sql.OpenDB(recordingConnector{d})
+	t.Cleanup(func() { conn.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func (d *recordingDB) record(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, statement)
+}
+
+// find returns the statements starting with prefix, in the order they ran.
+func (d *recordingDB) find(prefix string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var found []string
+	for _, statement := range d.statements {
+		if strings.HasPrefix(statement, prefix) {
+			found = append(found, statement)
+		}
+	}
+	return found
+}
+
+type recordingConnector struct {
+	db *recordingDB
+}
+
+func (c recordingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &recordingConn{db: c.db}, nil
+}
+
+func (c recordingConnector) Driver() driver.Driver {
+	return recordingDriver{}
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("use the connector")
+}
+
+type recordingConn struct {
+	db *recordingDB
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.db.record("BEGIN")
+	return recordingTx{c.db}, nil
+}
+
+func (c *recordingConn) Close() error {
+	return nil
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query)
+	var columns []string
+	var values [][]driver.Value
+	if c.db.rows != nil {
+		columns, values = c.db.rows(query)
+	}
+	return &recordedRows{columns: columns, values: values}, nil
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+type recordingTx struct {
+	db *recordingDB
+}
+
+func (tx recordingTx) Commit() error {
+	tx.db.record("COMMIT")
+	return nil
+}
+
+func (tx recordingTx) Rollback() error {
+	tx.db.record("ROLLBACK")
+	return nil
+}
+
+type recordedRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *recordedRows) Columns() []string { return r.columns }
+
+func (r *recordedRows) Close() error { return nil }
+
+func (r *recordedRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -3,9 +3,12 @@ package repository
 import (
 	"be-job-portal/internal/domain"
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type jobRepository struct {
@@ -17,27 +20,125 @@ func NewJobRepository(db *gorm.DB) domain.JobRepository {
 }
 
 func (r *jobRepository) Create(ctx context.Context, job *domain.Job) error {
-	return r.db.WithContext(ctx).Create(job).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return appendRevision(tx, job, &job.RecruiterID)
+	})
 }
 
-func (r *jobRepository) Update(ctx context.Context, job *domain.Job) error {
-	return r.db.WithContext(ctx).Save(job).Error
+func (r *jobRepository) UpdateStatus(ctx context.Context, job *domain.Job, from string) error {
+	result := r.db.WithContext(ctx).Model(&domain.Job{}).
+		Where("id = ? AND status = ?", job.ID, from).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"published_at": job.PublishedAt,
+			"closed_at":    job.ClosedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+func (r *jobRepository) UpdateContent(ctx context.Context, job *domain.Job, editorID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A new expiry deserves a new reminder.
+		err := tx.Model(&domain.Job{}).Where("id = ? AND expires_at IS DISTINCT FROM ?", job.ID, job.ExpiresAt).
+			Update("expiry_reminder_sent_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		// The update locks the job row, so concurrent edits number their revisions in turn.
+		result := updateJobContent(tx, job)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFound
+		}
+		return appendRevision(tx, job, &editorID)
+	})
+}
+
+// jobContentColumns are the columns a content edit writes: everything in a revision plus
+// the legacy salary text the edit may clear.
+var jobContentColumns = []string{
+	"title", "description", "category", "job_type",
+	"salary_min", "salary_max", "salary_currency", "salary_period", "salary_negotiable", "salary_legacy",
+	"benefits", "questions", "required_skills", "preferred_skills", "min_experience_years", "min_education",
+	"expires_at", "application_deadline", "updated_at",
 }
 
-func (r *jobRepository) GetAll(ctx context.Context, params domain.PaginationParams) ([]domain.Job, int64, error) {
+// updateJobContent writes the job's content columns only. Status, ownership and deletion
+// may all have changed since the job was read, and a deleted job is not brought back.
+func updateJobContent(tx *gorm.DB, job *domain.Job) *gorm.DB {
+	return tx.Model(job).Select(jobContentColumns).Updates(job)
+}
+
+// appendRevision stores the job's current content as its next revision.
+func appendRevision(tx *gorm.DB, job *domain.Job, editorID *uuid.UUID) error {
+	var last int
+	err := tx.Model(&domain.JobRevision{}).Where("job_id = ?", job.ID).Select("COALESCE(MAX(number), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&domain.JobRevision{
+		JobID:    job.ID,
+		Number:   last + 1,
+		EditorID: editorID,
+		Content:  job.Content(),
+	}).Error
+}
+
+func (r *jobRepository) ListRevisions(ctx context.Context, jobID uuid.UUID) ([]domain.JobRevision, error) {
+	var revisions []domain.JobRevision
+	if err := r.db.WithContext(ctx).Where("job_id = ?", jobID).Order("number DESC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *jobRepository) GetLatestRevision(ctx context.Context, jobID uuid.UUID) (*domain.JobRevision, error) {
+	var revision domain.JobRevision
+	if err := r.db.WithContext(ctx).Where("job_id = ?", jobID).Order("number DESC").First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// annualSalarySQL scales a salary amount column to a yearly figure so ranges quoted per
+// hour, month and year compare; an hourly rate assumes 2080 working hours.
+func annualSalarySQL(column string) string {
+	return fmt.Sprintf("(CASE jobs.salary_period WHEN '%[2]s' THEN jobs.%[1]s * 2080 WHEN '%[3]s' THEN jobs.%[1]s * 12 ELSE jobs.%[1]s END)",
+		column, domain.PayPeriodHour, domain.PayPeriodMonth)
+}
+
+// jobSearchConfig is the text search configuration behind jobs.search_vector. "simple"
+// does not stem, so postings in any language match on whole words.
+const jobSearchConfig = "simple"
+
+func (r *jobRepository) GetAll(ctx context.Context, filter domain.JobFilter) ([]domain.Job, int64, error) {
 	var jobs []domain.Job
 	var totalCount int64
 
-	if err := r.db.WithContext(ctx).Model(&domain.Job{}).Count(&totalCount).Error; err != nil {
+	query := filterJobs(r.db.WithContext(ctx).Model(&domain.Job{}), filter)
+
+	if err := query.Count(&totalCount).Error; err != nil {
 		return nil, 0, err
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	query = orderJobs(query, filter)
+	offset := (filter.Page - 1) * filter.Limit
 
-	if err := r.db.WithContext(ctx).
+	if err := query.
 		Preload("Company").
-		Order("created_at DESC").
-		Limit(params.Limit).
+		Limit(filter.Limit).
 		Offset(offset).
 		Find(&jobs).Error; err != nil {
 		return nil, 0, err
@@ -46,6 +147,132 @@ func (r *jobRepository) GetAll(ctx context.Context, params domain.PaginationPara
 	return jobs, totalCount, nil
 }
 
+func (r *jobRepository) Search(ctx context.Context, filter domain.JobFilter) ([]domain.JobSearchResult, int64, error) {
+	var totalCount int64
+
+	query := filterJobs(r.db.WithContext(ctx).Model(&domain.Job{}), filter)
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []struct {
+		ID   uuid.UUID
+		Rank float64
+	}
+	offset := (filter.Page - 1) * filter.Limit
+	err := orderJobs(query.Select("jobs.id, ts_rank(jobs.search_vector, websearch_to_tsquery(?, ?)) AS rank", jobSearchConfig, filter.Query), filter).
+		Limit(filter.Limit).
+		Offset(offset).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(hits) == 0 {
+		return []domain.JobSearchResult{}, totalCount, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+
+	// ts_headline is costly, so it only runs on the page being returned.
+	options := "StartSel=" + domain.HighlightStart + ", StopSel=" + domain.HighlightStop
+	var headlines []struct {
+		ID             uuid.UUID
+		TitleHighlight string
+		Snippet        string
+	}
+	err = r.db.WithContext(ctx).Model(&domain.Job{}).
+		Select(`jobs.id,
+			ts_headline(?, jobs.title, websearch_to_tsquery(?, ?), ?) AS title_highlight,
+			ts_headline(?, jobs.description, websearch_to_tsquery(?, ?), ?) AS snippet`,
+			jobSearchConfig, jobSearchConfig, filter.Query, options+", HighlightAll=true",
+			jobSearchConfig, jobSearchConfig, filter.Query, options+", MaxFragments=2, MaxWords=30, MinWords=10").
+		Where("jobs.id IN ?", ids).
+		Scan(&headlines).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []domain.Job
+	if err := r.db.WithContext(ctx).Preload("Company").Where("id IN ?", ids).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make(map[uuid.UUID]*domain.JobSearchResult, len(jobs))
+	for _, job := range jobs {
+		results[job.ID] = &domain.JobSearchResult{Job: job}
+	}
+	for _, headline := range headlines {
+		if result, ok := results[headline.ID]; ok {
+			result.TitleHighlight = headline.TitleHighlight
+			result.Snippet = headline.Snippet
+		}
+	}
+
+	ranked := make([]domain.JobSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if result, ok := results[hit.ID]; ok {
+			result.Rank = hit.Rank
+			ranked = append(ranked, *result)
+		}
+	}
+	return ranked, totalCount, nil
+}
+
+// filterJobs applies every JobFilter criterion except ordering and paging. Only
+// published jobs are ever listed, leaving out expired ones the scheduler has yet to close.
+func filterJobs(query *gorm.DB, filter domain.JobFilter) *gorm.DB {
+	query = query.Where("jobs.status = ? AND (jobs.expires_at IS NULL OR jobs.expires_at > now())", domain.JobStatusPublished)
+	if filter.Query != "" {
+		query = query.Where("jobs.search_vector @@ websearch_to_tsquery(?, ?)", jobSearchConfig, filter.Query)
+	}
+	if filter.Category != "" {
+		query = query.Where("LOWER(jobs.category) = LOWER(?)", filter.Category)
+	}
+	if filter.JobType != "" {
+		query = query.Where("LOWER(jobs.job_type) = LOWER(?)", filter.JobType)
+	}
+	if filter.Location != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM company_profiles cp WHERE cp.organization_id = jobs.organization_id AND cp.deleted_at IS NULL AND cp.location ILIKE ?)", "%"+escapeLike(filter.Location)+"%")
+	}
+	if filter.RecruiterID != nil {
+		query = query.Where("jobs.recruiter_id = ?", *filter.RecruiterID)
+	}
+	if filter.Currency != "" {
+		query = query.Where("jobs.salary_currency = ?", filter.Currency)
+	}
+	// A job matches when its yearly range overlaps the requested one.
+	if filter.MinSalary != nil {
+		query = query.Where(annualSalarySQL("salary_max")+" >= ?", *filter.MinSalary)
+	}
+	if filter.MaxSalary != nil {
+		query = query.Where(annualSalarySQL("salary_min")+" <= ?", *filter.MaxSalary)
+	}
+	return query
+}
+
+// orderJobs applies the requested ordering, newest first among equals.
+func orderJobs(query *gorm.DB, filter domain.JobFilter) *gorm.DB {
+	switch filter.Sort {
+	case domain.JobSortSalaryDesc:
+		query = query.Order(annualSalarySQL("salary_max") + " DESC NULLS LAST")
+	case domain.JobSortSalaryAsc:
+		query = query.Order(annualSalarySQL("salary_min") + " ASC NULLS LAST")
+	case domain.JobSortRelevance:
+		if filter.Query != "" {
+			// GORM ignores a bare clause.Expr in Order and drops an expression once another
+			// column is merged in, so the tie-breaker has to be part of the same expression.
+			return query.Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "ts_rank(jobs.search_vector, websearch_to_tsquery(?, ?)) DESC, jobs.created_at DESC",
+				Vars: []interface{}{jobSearchConfig, filter.Query},
+			}})
+		}
+	}
+	return query.Order("jobs.created_at DESC")
+}
+
 func (r *jobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
 	var job domain.Job
 	if err := r.db.WithContext(ctx).Preload("Company").First(&job, "id = ?", id).Error; err != nil {
@@ -54,6 +281,35 @@ func (r *jobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Job,
 	return &job, nil
 }
 
+func (r *jobRepository) GetByIDWithDeleted(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	var job domain.Job
+	if err := r.db.WithContext(ctx).Unscoped().Preload("Company").First(&job, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.Job{}, "id = ?", id).Error
+}
+
+func (r *jobRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&domain.Job{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *jobRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var applications int64
+		if err := tx.Unscoped().Model(&domain.Application{}).Where("job_id = ?", id).Count(&applications).Error; err != nil {
+			return err
+		}
+		if applications > 0 {
+			return domain.ErrConflict
+		}
+		return tx.Unscoped().Delete(&domain.Job{}, "id = ?", id).Error
+	})
+}
+
 func (r *jobRepository) GetByRecruiterID(ctx context.Context, recruiterID uuid.UUID) ([]domain.Job, error) {
 	var jobs []domain.Job
 	if err := r.db.WithContext(ctx).Preload("Company").Where("recruiter_id = ?", recruiterID).Order("created_at DESC").Find(&jobs).Error; err != nil {
@@ -61,3 +317,44 @@ func (r *jobRepository) GetByRecruiterID(ctx context.Context, recruiterID uuid.U
 	}
 	return jobs, nil
 }
+
+func (r *jobRepository) GetByOrganizationID(ctx context.Context, orgID uuid.UUID) ([]domain.Job, error) {
+	var jobs []domain.Job
+	if err := r.db.WithContext(ctx).Preload("Company").Where("organization_id = ?", orgID).Order("created_at DESC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) ListExpired(ctx context.Context, now time.Time) ([]domain.Job, error) {
+	var jobs []domain.Job
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND expires_at <= ?", []string{domain.JobStatusPublished, domain.JobStatusPaused}, now).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) CloseIfExpired(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Job{}).
+		Where("id = ? AND status IN ? AND expires_at <= ?", id, []string{domain.JobStatusPublished, domain.JobStatusPaused}, now).
+		Updates(map[string]interface{}{"status": domain.JobStatusClosed, "closed_at": now})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *jobRepository) ListExpiringSoon(ctx context.Context, now, by time.Time) ([]domain.Job, error) {
+	var jobs []domain.Job
+	err := r.db.WithContext(ctx).Preload("Recruiter").
+		Where("status IN ? AND expires_at > ? AND expires_at <= ? AND expiry_reminder_sent_at IS NULL", []string{domain.JobStatusPublished, domain.JobStatusPaused}, now, by).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) MarkExpiryReminderSent(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.Job{}).Where("id = ?", id).Update("expiry_reminder_sent_at", at).Error
+}
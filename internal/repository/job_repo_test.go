@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds statements without a database behind it.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=dry_run"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestUpdateJobContentOnlyWritesContent(t *testing.T) {
+	job := &domain.Job{ID: uuid.New(), Title: "Backend Engineer", Status: domain.JobStatusPublished, OrganizationID: uuid.New(), RecruiterID: uuid.New()}
+	result := updateJobContent(dryRunDB(t), job)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+
+	sql := result.Statement.SQL.String()
+	set, where, found := strings.Cut(sql, " WHERE ")
+	if !found {
+		t.Fatalf("content update has no WHERE clause: %s", sql)
+	}
+	for _, column := range []string{`"status"`, `"published_at"`, `"closed_at"`, `"deleted_at"`, `"organization_id"`, `"recruiter_id"`, `"created_at"`, `"expiry_reminder_sent_at"`} {
+		if strings.Contains(set, column) {
+			t.Errorf("content update writes %s: %s", column, sql)
+		}
+	}
+	if !strings.Contains(where, `"deleted_at" IS NULL`) {
+		t.Errorf("content update can reach a deleted job: %s", sql)
+	}
+	for _, column := range []string{`"title"`, `"salary_min"`, `"salary_legacy"`, `"questions"`, `"required_skills"`, `"expires_at"`, `"updated_at"`} {
+		if !strings.Contains(set, column+"=") {
+			t.Errorf("content update does not write %s: %s", column, sql)
+		}
+	}
+}
+
+func TestDeletedJobsAreHiddenUntilRestored(t *testing.T) {
+	ctx := context.Background()
+	recorder := &recordingDB{}
+	repo := NewJobRepository(recorder.open(t))
+	id := uuid.New()
+
+	if err := repo.Delete(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	deletes := recorder.find(`UPDATE "jobs" SET "deleted_at"=`)
+	if len(deletes) != 1 || len(recorder.find("DELETE")) != 0 {
+		t.Fatalf("delete is not a soft delete: %q", recorder.statements)
+	}
+
+	// Every read but GetByIDWithDeleted leaves out deleted jobs.
+	repo.GetAll(ctx, domain.JobFilter{PaginationParams: domain.PaginationParams{Page: 1, Limit: 10}})
+	repo.Search(ctx, domain.JobFilter{Query: "go", PaginationParams: domain.PaginationParams{Page: 1, Limit: 10}})
+	repo.GetByID(ctx, id)
+	reads := recorder.find("SELECT")
+	if len(reads) == 0 {
+		t.Fatal("nothing was read")
+	}
+	for _, query := range reads {
+		if !strings.Contains(query, `"jobs"."deleted_at" IS NULL`) {
+			t.Errorf("read can return deleted jobs: %s", query)
+		}
+	}
+	repo.GetByIDWithDeleted(ctx, id)
+	if query := recorder.statements[len(recorder.statements)-1]; strings.Contains(query, "deleted_at") {
+		t.Errorf("GetByIDWithDeleted leaves out deleted jobs: %s", query)
+	}
+
+	if err := repo.Restore(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	restore := recorder.statements[len(recorder.statements)-1]
+	if !strings.HasPrefix(restore, `UPDATE "jobs" SET "deleted_at"=`) || strings.Contains(restore, "IS NULL") {
+		t.Fatalf("restore cannot reach a deleted job: %s", restore)
+	}
+}
+
+func TestPurgeKeepsJobsWithApplications(t *testing.T) {
+	for _, applications := range []int64{0, 1} {
+		t.Run(fmt.Sprint(applications, " applications"), func(t *testing.T) {
+			recorder := &recordingDB{rows: func(query string) ([]string, [][]driver.Value) {
+				return []string{"count"}, [][]driver.Value{{applications}}
+			}}
+			err := NewJobRepository(recorder.open(t)).Purge(context.Background(), uuid.New())
+
+			// Soft-deleted applications are still candidate history, so they count too.
+			counts := recorder.find(`SELECT count(*) FROM "applications"`)
+			if len(counts) != 1 || strings.Contains(counts[0], "deleted_at") {
+				t.Fatalf("applications were not all counted: %q", recorder.statements)
+			}
+			deletes := recorder.find(`DELETE FROM "jobs"`)
+			if applications > 0 {
+				if !errors.Is(err, domain.ErrConflict) {
+					t.Fatalf("err = %v, want ErrConflict", err)
+				}
+				if len(deletes) != 0 || len(recorder.find("ROLLBACK")) != 1 {
+					t.Fatalf("job with applications was purged: %q", recorder.statements)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(deletes) != 1 || strings.Contains(deletes[0], "deleted_at") || len(recorder.find("COMMIT")) != 1 {
+				t.Fatalf("job was not removed for good: %q", recorder.statements)
+			}
+		})
+	}
+}
+
+// listedOnly is the predicate that keeps drafts, paused, closed and expired jobs out of public listings.
+const listedOnly = `WHERE (jobs.status = $1 AND (jobs.expires_at IS NULL OR jobs.expires_at > now()))`
+
+func TestFilterJobsOnlyListsPublishedUnexpiredJobs(t *testing.T) {
+	recruiterID := uuid.New()
+	low, high := int64(60000000), int64(120000000)
+
+	tests := []struct {
+		name   string
+		filter domain.JobFilter
+		want   []string
+		vars   []interface{}
+	}{
+		{
+			name: "no criteria",
+		},
+		{
+			name:   "category and type ignore case",
+			filter: domain.JobFilter{Category: "Engineering", JobType: "full-time"},
+			want:   []string{"LOWER(jobs.category) = LOWER($2)", "LOWER(jobs.job_type) = LOWER($3)"},
+			vars:   []interface{}{"Engineering", "full-time"},
+		},
+		{
+			name:   "location escapes wildcards",
+			filter: domain.JobFilter{Location: "50%_off"},
+			want:   []string{"cp.organization_id = jobs.organization_id AND cp.deleted_at IS NULL AND cp.location ILIKE $2"},
+			vars:   []interface{}{`%50\%\_off%`},
+		},
+		{
+			name:   "recruiter",
+			filter: domain.JobFilter{RecruiterID: &recruiterID},
+			want:   []string{"jobs.recruiter_id = $2"},
+			vars:   []interface{}{recruiterID},
+		},
+		{
+			name:   "salary range overlaps in yearly terms",
+			filter: domain.JobFilter{Currency: "IDR", MinSalary: &low, MaxSalary: &high},
+			want: []string{
+				"jobs.salary_currency = $2",
+				annualSalarySQL("salary_max") + " >= $3",
+				annualSalarySQL("salary_min") + " <= $4",
+			},
+			vars: []interface{}{"IDR", low, high},
+		},
+		{
+			name:   "text query",
+			filter: domain.JobFilter{Query: "golang"},
+			want:   []string{"jobs.search_vector @@ websearch_to_tsquery($2, $3)"},
+			vars:   []interface{}{jobSearchConfig, "golang"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var jobs []domain.Job
+			result := filterJobs(dryRunDB(t).Model(&domain.Job{}), tt.filter).Find(&jobs)
+			if result.Error != nil {
+				t.Fatal(result.Error)
+			}
+
+			sql := result.Statement.SQL.String()
+			if !strings.Contains(sql, listedOnly) || !strings.Contains(sql, `"jobs"."deleted_at" IS NULL`) {
+				t.Fatalf("listing is not limited to published, unexpired, undeleted jobs: %s", sql)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(sql, want) {
+					t.Errorf("listing lacks %s: %s", want, sql)
+				}
+			}
+			vars := result.Statement.Vars
+			if len(vars) != 1+len(tt.vars) || vars[0] != domain.JobStatusPublished {
+				t.Fatalf("vars = %v, want %q then %v", vars, domain.JobStatusPublished, tt.vars)
+			}
+			for i, want := range tt.vars {
+				if vars[i+1] != want {
+					t.Errorf("var %d = %v, want %v", i+1, vars[i+1], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllCountsAndPagesTheSameJobs(t *testing.T) {
+	recorder := &recordingDB{}
+	_, _, err := NewJobRepository(recorder.open(t)).GetAll(context.Background(), domain.JobFilter{
+		Category:         "Engineering",
+		Sort:             domain.JobSortSalaryDesc,
+		PaginationParams: domain.PaginationParams{Page: 3, Limit: 20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := recorder.find("SELECT count(*)")
+	pages := recorder.find(`SELECT * FROM "jobs"`)
+	if len(counts) != 1 || len(pages) != 1 {
+		t.Fatalf("statements = %q", recorder.statements)
+	}
+	for _, query := range []string{counts[0], pages[0]} {
+		if !strings.Contains(query, listedOnly) || !strings.Contains(query, "LOWER(jobs.category) = LOWER($2)") {
+			t.Errorf("query is not filtered like the listing: %s", query)
+		}
+	}
+	if want := "ORDER BY " + annualSalarySQL("salary_max") + " DESC NULLS LAST,jobs.created_at DESC LIMIT $3 OFFSET $4"; !strings.HasSuffix(pages[0], want) {
+		t.Errorf("page query = %s, want it to end with %s", pages[0], want)
+	}
+}
+
+func TestSearchRanksOnlyListedJobs(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	recorder := &recordingDB{rows: func(query string) ([]string, [][]driver.Value) {
+		switch {
+		case strings.HasPrefix(query, "SELECT count(*)"):
+			return []string{"count"}, [][]driver.Value{{int64(2)}}
+		case strings.Contains(query, "ts_rank"):
+			return []string{"id", "rank"}, [][]driver.Value{{first.String(), 0.9}, {second.String(), 0.4}}
+		case strings.Contains(query, "ts_headline"):
+			return []string{"id", "title_highlight", "snippet"}, [][]driver.Value{
+				{second.String(), "Python", "likes \x02go\x03 too"},
+				{first.String(), "\x02Go\x03 Engineer", "writes \x02Go\x03"},
+			}
+		case strings.HasPrefix(query, `SELECT * FROM "jobs"`):
+			// Fetched by id, so in no particular order.
+			return []string{"id", "title"}, [][]driver.Value{{second.String(), "Python"}, {first.String(), "Go Engineer"}}
+		}
+		return nil, nil
+	}}
+
+	results, total, err := NewJobRepository(recorder.open(t)).Search(context.Background(), domain.JobFilter{
+		Query:            "go",
+		Sort:             domain.JobSortRelevance,
+		PaginationParams: domain.PaginationParams{Page: 1, Limit: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := recorder.find("SELECT count(*)")
+	ranks := recorder.find("SELECT jobs.id, ts_rank")
+	if len(counts) != 1 || len(ranks) != 1 {
+		t.Fatalf("statements = %q", recorder.statements)
+	}
+	// The ranking query selects ts_rank first, which shifts its placeholders by two.
+	for query, match := range map[string]string{
+		counts[0]: listedOnly + " AND jobs.search_vector @@ websearch_to_tsquery($2, $3)",
+		ranks[0]:  strings.Replace(listedOnly, "$1", "$3", 1) + " AND jobs.search_vector @@ websearch_to_tsquery($4, $5)",
+	} {
+		if !strings.Contains(query, match) || !strings.Contains(query, `"jobs"."deleted_at" IS NULL`) {
+			t.Errorf("search is not limited to published, unexpired, undeleted matches: %s", query)
+		}
+	}
+	if !strings.Contains(ranks[0], "ORDER BY ts_rank(jobs.search_vector, websearch_to_tsquery($6, $7)) DESC, jobs.created_at DESC LIMIT") {
+		t.Errorf("hits are not ordered by rank: %s", ranks[0])
+	}
+	headlines := recorder.find("SELECT jobs.id,\n")
+	if len(headlines) != 1 || !strings.Contains(headlines[0], "WHERE jobs.id IN ($") {
+		t.Errorf("headlines are not limited to the page: %q", headlines)
+	}
+
+	if total != 2 || len(results) != 2 {
+		t.Fatalf("got %d of %d results", len(results), total)
+	}
+	want := []domain.JobSearchResult{
+		{Job: domain.Job{ID: first, Title: "Go Engineer"}, Rank: 0.9, TitleHighlight: "\x02Go\x03 Engineer", Snippet: "writes \x02Go\x03"},
+		{Job: domain.Job{ID: second, Title: "Python"}, Rank: 0.4, TitleHighlight: "Python", Snippet: "likes \x02go\x03 too"},
+	}
+	for i, result := range results {
+		if result.ID != want[i].ID || result.Title != want[i].Title || result.Rank != want[i].Rank || result.TitleHighlight != want[i].TitleHighlight || result.Snippet != want[i].Snippet {
+			t.Errorf("result %d = %+v, want %+v", i, result, want[i])
+		}
+	}
+}
+
+func TestSearchWithoutHitsSkipsHeadlines(t *testing.T) {
+	recorder := &recordingDB{rows: func(query string) ([]string, [][]driver.Value) {
+		if strings.HasPrefix(query, "SELECT count(*)") {
+			return []string{"count"}, [][]driver.Value{{int64(0)}}
+		}
+		return nil, nil
+	}}
+	results, total, err := NewJobRepository(recorder.open(t)).Search(context.Background(), domain.JobFilter{
+		Query:            "cobol",
+		PaginationParams: domain.PaginationParams{Page: 1, Limit: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results == nil || len(results) != 0 || total != 0 {
+		t.Fatalf("results = %v, total %d, want an empty list", results, total)
+	}
+	if len(recorder.statements) != 2 {
+		t.Fatalf("statements = %q, want only the count and the ranking", recorder.statements)
+	}
+}
+
+func TestOrderJobs(t *testing.T) {
+	tests := []struct {
+		filter domain.JobFilter
+		want   string
+	}{
+		{filter: domain.JobFilter{Sort: domain.JobSortNewest}, want: "ORDER BY jobs.created_at DESC"},
+		{filter: domain.JobFilter{Sort: domain.JobSortSalaryDesc}, want: "ORDER BY " + annualSalarySQL("salary_max") + " DESC NULLS LAST,jobs.created_at DESC"},
+		{filter: domain.JobFilter{Sort: domain.JobSortSalaryAsc}, want: "ORDER BY " + annualSalarySQL("salary_min") + " ASC NULLS LAST,jobs.created_at DESC"},
+		{filter: domain.JobFilter{Sort: domain.JobSortRelevance, Query: "go"}, want: "ORDER BY ts_rank(jobs.search_vector, websearch_to_tsquery($1, $2)) DESC, jobs.created_at DESC"},
+		// Without a query there is nothing to rank by.
+		{filter: domain.JobFilter{Sort: domain.JobSortRelevance}, want: "ORDER BY jobs.created_at DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.filter.Sort+" "+tt.filter.Query), func(t *testing.T) {
+			var jobs []domain.Job
+			result := orderJobs(dryRunDB(t).Unscoped(), tt.filter).Find(&jobs)
+			if result.Error != nil {
+				t.Fatal(result.Error)
+			}
+			if sql := result.Statement.SQL.String(); !strings.HasSuffix(sql, tt.want) {
+				t.Fatalf("sql = %s, want it to end with %s", sql, tt.want)
+			}
+		})
+	}
+}
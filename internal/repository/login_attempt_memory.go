@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"be-job-portal/internal/domain"
+)
+
+type memoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+}
+
+// NewMemoryLoginAttemptRepository keeps throttling state in process. It is only
+// accurate for a single instance, so use the Postgres store behind a load balancer.
+func NewMemoryLoginAttemptRepository() domain.LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{attempts: make(map[string]domain.LoginAttempt)}
+}
+
+func (r *memoryLoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pruneLocked(now, window)
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = domain.LoginAttempt{Key: key}
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	r.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		r.attempts[key] = attempt
+	}
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// pruneLocked drops entries whose last failure or lockout ended more than window ago.
+// Callers must hold r.mu.
+func (r *memoryLoginAttemptRepository) pruneLocked(now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	for key, attempt := range r.attempts {
+		last := attempt.LastFailedAt
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(last) {
+			last = *attempt.LockedUntil
+		}
+		if last.Before(cutoff) {
+			delete(r.attempts, key)
+		}
+	}
+}
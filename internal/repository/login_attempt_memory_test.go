@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-job-portal/internal/domain"
+)
+
+func TestMemoryLoginAttemptRepository(t *testing.T) {
+	ctx := context.Background()
+	window := 15 * time.Minute
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// run records failures and locks, returning the key to inspect.
+		run          func(r domain.LoginAttemptRepository) string
+		wantFailures int
+		wantLocked   bool
+	}{
+		{
+			name: "failures within the window add up",
+			run: func(r domain.LoginAttemptRepository) string {
+				for i := 0; i < 3; i++ {
+					r.RecordFailure(ctx, "email:a", start.Add(time.Duration(i)*time.Minute), window)
+				}
+				return "email:a"
+			},
+			wantFailures: 3,
+		},
+		{
+			name: "a failure after the window starts over",
+			run: func(r domain.LoginAttemptRepository) string {
+				r.RecordFailure(ctx, "email:a", start, window)
+				r.RecordFailure(ctx, "email:a", start.Add(time.Minute), window)
+				r.RecordFailure(ctx, "email:a", start.Add(time.Minute+window+time.Second), window)
+				return "email:a"
+			},
+			wantFailures: 1,
+		},
+		{
+			name: "the window runs from the end of a lockout",
+			run: func(r domain.LoginAttemptRepository) string {
+				r.RecordFailure(ctx, "email:a", start, window)
+				r.Lock(ctx, "email:a", start.Add(time.Hour))
+				r.RecordFailure(ctx, "email:a", start.Add(time.Hour+window-time.Second), window)
+				return "email:a"
+			},
+			wantFailures: 2,
+			wantLocked:   true,
+		},
+		{
+			name: "keys are counted apart",
+			run: func(r domain.LoginAttemptRepository) string {
+				r.RecordFailure(ctx, "email:a", start, window)
+				r.RecordFailure(ctx, "ip:203.0.113.7", start, window)
+				r.RecordFailure(ctx, "ip:203.0.113.7", start, window)
+				return "email:a"
+			},
+			wantFailures: 1,
+		},
+		{
+			name: "reset forgets the key",
+			run: func(r domain.LoginAttemptRepository) string {
+				r.RecordFailure(ctx, "email:a", start, window)
+				r.Lock(ctx, "email:a", start.Add(time.Minute))
+				r.Reset(ctx, "email:a")
+				return "email:a"
+			},
+		},
+		{
+			name: "locking an unknown key records nothing",
+			run: func(r domain.LoginAttemptRepository) string {
+				r.Lock(ctx, "email:a", start.Add(time.Minute))
+				return "email:a"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewMemoryLoginAttemptRepository()
+			key := tt.run(r)
+
+			attempt, err := r.Get(ctx, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantFailures == 0 {
+				if attempt != nil {
+					t.Fatalf("attempt = %+v, want none", attempt)
+				}
+				return
+			}
+			if attempt == nil || attempt.Failures != tt.wantFailures || (attempt.LockedUntil != nil) != tt.wantLocked {
+				t.Fatalf("attempt = %+v, want %d failures, locked %v", attempt, tt.wantFailures, tt.wantLocked)
+			}
+		})
+	}
+}
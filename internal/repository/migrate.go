@@ -0,0 +1,427 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Migrate brings the schema up to date and backfills data for columns added after launch.
+// Every step is idempotent so it can run on each start.
+func Migrate(db *gorm.DB) error {
+	// Company profiles used to be unique per user and jobs referenced them by recruiter;
+	// both now hang off the organization instead.
+	if db.Migrator().HasConstraint(&domain.Job{}, "fk_jobs_company") {
+		if err := db.Migrator().DropConstraint(&domain.Job{}, "fk_jobs_company"); err != nil {
+			return err
+		}
+	}
+	if err := dropUniqueIndex(db, "idx_company_profiles_user_id"); err != nil {
+		return err
+	}
+	// Applications must outlive their job, so no database may still delete them along
+	// with it; a job with applications cannot be purged at all.
+	if err := dropCascadingForeignKey(db, "applications", "fk_applications_job"); err != nil {
+		return err
+	}
+
+	if err := addEmailVerifiedAt(db); err != nil {
+		return err
+	}
+	if err := addJobStatus(db); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(&domain.User{}, &domain.Organization{}, &domain.OrganizationMember{}, &domain.OrganizationInvitation{}, &domain.Job{}, &domain.JobRevision{}, &domain.Application{}, &domain.SeekerProfile{}, &domain.CompanyProfile{}, &domain.Experience{}, &domain.Education{}, &domain.Session{}, &domain.RotatedRefreshToken{}, &domain.UserToken{}, &domain.RecoveryCode{}, &domain.LoginAttempt{}, &domain.OAuthState{}, &domain.UserIdentity{}, &domain.APIKey{}, &domain.AuditEvent{})
+	if err != nil {
+		return err
+	}
+
+	// Jobs and company profiles belonged to recruiters directly before organizations.
+	// Nobody can act on rows left without one, so this runs on every start until none are.
+	if err := backfillOrganizations(db); err != nil {
+		return err
+	}
+
+	if err := migrateLegacySalaries(db); err != nil {
+		return err
+	}
+	if err := protectAuditEvents(db); err != nil {
+		return err
+	}
+	if err := protectJobRevisions(db); err != nil {
+		return err
+	}
+	if err := backfillJobRevisions(db); err != nil {
+		return err
+	}
+	return addJobSearchVector(db)
+}
+
+// addEmailVerifiedAt adds the email_verified_at column to a users table that predates
+// it. Google logins came before email verification and Google only issued verified
+// addresses, so those users count as verified since they signed up. Like addJobStatus,
+// the column and the backfill are one transaction, so an interrupted start is retried.
+func addEmailVerifiedAt(db *gorm.DB) error {
+	if !db.Migrator().HasTable("users") || db.Migrator().HasColumn("users", "email_verified_at") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("ALTER TABLE users ADD COLUMN email_verified_at timestamptz").Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE users SET email_verified_at = created_at WHERE provider = ?", "google").Error
+	})
+}
+
+// addJobStatus adds the status column to a jobs table that predates it. Jobs were live
+// as soon as they were posted back then, so existing jobs are published as of their
+// creation and only later jobs start as drafts. The column, the backfill and the draft
+// default are one transaction: a start that fails midway leaves no column behind, and
+// the next start does it all again.
+func addJobStatus(db *gorm.DB) error {
+	if !db.Migrator().HasTable("jobs") || db.Migrator().HasColumn("jobs", "status") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			"ALTER TABLE jobs ADD COLUMN IF NOT EXISTS published_at timestamptz",
+			"ALTER TABLE jobs ADD COLUMN status varchar(16) NOT NULL DEFAULT '" + domain.JobStatusPublished + "'",
+			"UPDATE jobs SET published_at = created_at WHERE published_at IS NULL",
+			"ALTER TABLE jobs ALTER COLUMN status SET DEFAULT '" + domain.JobStatusDraft + "'",
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateLegacySalaries moves the free-text jobs.salary column into the structured
+// salary columns and then drops it, all in one transaction so a failure retries on the
+// next start. When the text cannot be fully read it is kept in salary_legacy, so
+// recruiters can see what the job used to say while filling the range in.
+func migrateLegacySalaries(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("jobs", "salary") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID     string
+			Salary string
+		}
+		err := tx.Table("jobs").Select("id, salary").Where("salary IS NOT NULL AND salary <> ''").Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			salary := parseLegacySalary(row.Salary)
+			// Whatever the parser could not place is kept verbatim for the recruiter.
+			var legacy interface{}
+			if salary.Min == nil || salary.Currency == "" || salary.Period == "" {
+				legacy = row.Salary
+			}
+			err := tx.Table("jobs").Where("id = ?", row.ID).Updates(map[string]interface{}{
+				"salary_min":        salary.Min,
+				"salary_max":        salary.Max,
+				"salary_currency":   salary.Currency,
+				"salary_period":     salary.Period,
+				"salary_negotiable": salary.Negotiable,
+				"salary_legacy":     legacy,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn("jobs", "salary")
+	})
+}
+
+var (
+	legacySalaryAmount = regexp.MustCompile(`(\d[\d.,]*)\s*(k|rb|ribu|jt|juta|mio|m)?\b`)
+	legacySalaryHour   = regexp.MustCompile(`\b(hour|hourly|hr|jam)\b|/h\b`)
+	legacySalaryYear   = regexp.MustCompile(`\b(year|yearly|yr|annum|annual|annually|tahun|pa)\b`)
+	legacySalaryMonth  = regexp.MustCompile(`\b(month|monthly|mo|bulan)\b`)
+	legacySalaryIDR    = regexp.MustCompile(`\b(rp|idr)|\d\s*(rb|ribu|jt|juta)\b`)
+)
+
+// legacySalaryCurrencies maps markers found in free-text salaries to ISO 4217 codes,
+// checked in order so "s$" wins over "$".
+var legacySalaryCurrencies = []struct{ marker, code string }{
+	{"usd", "USD"}, {"sgd", "SGD"}, {"s$", "SGD"}, {"eur", "EUR"}, {"€", "EUR"}, {"gbp", "GBP"}, {"£", "GBP"}, {"$", "USD"},
+}
+
+// parseLegacySalary makes a best-effort reading of strings such as "Rp 5.000.000 -
+// 7.000.000 per month", "$80k-100k/year" or "5-7 juta, negotiable". Currency and period
+// stay empty when the text does not state them.
+func parseLegacySalary(text string) domain.Salary {
+	text = strings.ToLower(text)
+	salary := domain.Salary{Negotiable: strings.Contains(text, "nego")}
+
+	if legacySalaryIDR.MatchString(text) {
+		salary.Currency = "IDR"
+	} else {
+		for _, currency := range legacySalaryCurrencies {
+			if strings.Contains(text, currency.marker) {
+				salary.Currency = currency.code
+				break
+			}
+		}
+	}
+
+	switch {
+	case legacySalaryHour.MatchString(text):
+		salary.Period = domain.PayPeriodHour
+	case legacySalaryYear.MatchString(text):
+		salary.Period = domain.PayPeriodYear
+	case legacySalaryMonth.MatchString(text):
+		salary.Period = domain.PayPeriodMonth
+	}
+
+	var amounts []float64
+	var multipliers []float64
+	for _, match := range legacySalaryAmount.FindAllStringSubmatch(text, 2) {
+		amount, ok := parseLegacyAmount(match[1])
+		if !ok {
+			continue
+		}
+		multiplier := 1.0
+		switch match[2] {
+		case "k", "rb", "ribu":
+			multiplier = 1e3
+		case "jt", "juta", "mio", "m":
+			multiplier = 1e6
+		}
+		amounts = append(amounts, amount)
+		multipliers = append(multipliers, multiplier)
+	}
+	if len(amounts) == 0 {
+		return salary
+	}
+
+	// "5-7 juta" abbreviates both ends with the last suffix.
+	last := multipliers[len(multipliers)-1]
+	for i := range amounts {
+		if multipliers[i] == 1 && amounts[i] < 1000 {
+			multipliers[i] = last
+		}
+		amounts[i] *= multipliers[i]
+	}
+
+	min, max := int64(math.Round(amounts[0])), int64(math.Round(amounts[len(amounts)-1]))
+	if min > max {
+		min, max = max, min
+	}
+	salary.Min, salary.Max = &min, &max
+	return salary
+}
+
+// parseLegacyAmount reads "5.000.000", "1,500.50" or "5.5". Separators followed by
+// groups of exactly three digits are thousands separators; otherwise the last one is
+// the decimal point.
+func parseLegacyAmount(number string) (float64, bool) {
+	number = strings.TrimRight(number, ".,")
+	groups := strings.FieldsFunc(number, func(r rune) bool { return r == '.' || r == ',' })
+	if len(groups) == 0 {
+		return 0, false
+	}
+
+	thousands := true
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			thousands = false
+		}
+	}
+	if !thousands {
+		last := len(groups) - 1
+		number = strings.Join(groups[:last], "") + "." + groups[last]
+	} else {
+		number = strings.Join(groups, "")
+	}
+
+	amount, err := strconv.ParseFloat(number, 64)
+	return amount, err == nil
+}
+
+// addJobSearchVector adds the generated full-text column behind job search. Title and
+// category weigh more than the description and benefits when ranking.
+func addJobSearchVector(db *gorm.DB) error {
+	err := db.Exec(fmt.Sprintf(`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('%[1]s', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('%[1]s', coalesce(category, '')), 'B') ||
+		setweight(to_tsvector('%[1]s', coalesce(description, '')), 'C') ||
+		setweight(to_tsvector('%[1]s', coalesce(benefits, '')), 'D')
+	) STORED`, jobSearchConfig)).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_jobs_search_vector ON jobs USING GIN (search_vector)").Error
+}
+
+// protectAuditEvents makes the audit trail append-only: a trigger rejects any UPDATE or
+// DELETE, so rows cannot be rewritten even by code that bypasses the repository.
+func protectAuditEvents(db *gorm.DB) error {
+	err := db.Exec(`CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql`).Error
+	if err != nil {
+		return err
+	}
+	if err := db.Exec("DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events").Error; err != nil {
+		return err
+	}
+	return db.Exec(`CREATE TRIGGER audit_events_append_only
+	BEFORE UPDATE OR DELETE ON audit_events
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only()`).Error
+}
+
+// protectJobRevisions keeps revisions from being rewritten. Deletes stay allowed so a
+// purged job takes its revisions with it.
+func protectJobRevisions(db *gorm.DB) error {
+	err := db.Exec(`CREATE OR REPLACE FUNCTION job_revisions_immutable() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'job_revisions are immutable';
+END;
+$$ LANGUAGE plpgsql`).Error
+	if err != nil {
+		return err
+	}
+	if err := db.Exec("DROP TRIGGER IF EXISTS job_revisions_immutable ON job_revisions").Error; err != nil {
+		return err
+	}
+	return db.Exec(`CREATE TRIGGER job_revisions_immutable
+	BEFORE UPDATE ON job_revisions
+	FOR EACH STATEMENT EXECUTE FUNCTION job_revisions_immutable()`).Error
+}
+
+// backfillJobRevisions records the current content of jobs that predate revisions as
+// their first revision. Applications made before then keep no revision.
+func backfillJobRevisions(db *gorm.DB) error {
+	var jobs []domain.Job
+	err := db.Unscoped().Where("NOT EXISTS (SELECT 1 FROM job_revisions r WHERE r.job_id = jobs.id)").Find(&jobs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		revision := &domain.JobRevision{
+			CreatedAt: job.UpdatedAt,
+			JobID:     job.ID,
+			Number:    1,
+			EditorID:  &job.RecruiterID,
+			Content:   job.Content(),
+		}
+		if err := db.Create(revision).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillOrganizations gives every recruiter who still owns jobs or a company profile
+// without an organization a team of their own that takes them over, or hands the rows
+// to the organization they have joined since. Recruiters with nothing to carry over get
+// an organization when they first need one. Each recruiter is moved in one transaction,
+// so an interrupted run picks up where it stopped.
+func backfillOrganizations(db *gorm.DB) error {
+	var users []domain.User
+	err := db.Where("EXISTS (SELECT 1 FROM jobs j WHERE j.recruiter_id = users.id AND j.organization_id IS NULL) OR EXISTS (SELECT 1 FROM company_profiles p WHERE p.user_id = users.id AND p.organization_id IS NULL)").
+		Find(&users).Error
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			orgID, err := legacyOrganization(tx, user)
+			if err != nil {
+				return err
+			}
+
+			err = tx.Unscoped().Model(&domain.CompanyProfile{}).
+				Where("user_id = ? AND organization_id IS NULL", user.ID).
+				Update("organization_id", orgID).Error
+			if err != nil {
+				return err
+			}
+			return tx.Unscoped().Model(&domain.Job{}).
+				Where("recruiter_id = ? AND organization_id IS NULL", user.ID).
+				Update("organization_id", orgID).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyOrganization returns the organization user belongs to, creating one they own,
+// named after their company profile, if they have none.
+func legacyOrganization(tx *gorm.DB, user domain.User) (uuid.UUID, error) {
+	var member domain.OrganizationMember
+	err := tx.Where("user_id = ?", user.ID).First(&member).Error
+	if err == nil {
+		return member.OrganizationID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, err
+	}
+
+	name := user.Email
+	var profile domain.CompanyProfile
+	if err := tx.Where("user_id = ?", user.ID).First(&profile).Error; err == nil && profile.CompanyName != "" {
+		name = profile.CompanyName
+	}
+
+	org := &domain.Organization{Name: name}
+	if err := tx.Create(org).Error; err != nil {
+		return uuid.Nil, err
+	}
+	member = domain.OrganizationMember{OrganizationID: org.ID, UserID: user.ID, Role: domain.OrgRoleOwner}
+	if err := tx.Create(&member).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return org.ID, nil
+}
+
+func dropUniqueIndex(db *gorm.DB, name string) error {
+	var definition string
+	err := db.Raw("SELECT indexdef FROM pg_indexes WHERE indexname = ?", name).Scan(&definition).Error
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(definition, "UNIQUE") {
+		return nil
+	}
+	return db.Exec("DROP INDEX IF EXISTS " + name).Error
+}
+
+// dropCascadingForeignKey drops a foreign key declared ON DELETE CASCADE so AutoMigrate
+// recreates it with the rule now on the model.
+func dropCascadingForeignKey(db *gorm.DB, table, name string) error {
+	var action string
+	err := db.Raw("SELECT confdeltype::text FROM pg_constraint WHERE conname = ?", name).Scan(&action).Error
+	if err != nil {
+		return err
+	}
+	if action != "c" {
+		return nil
+	}
+	return db.Exec("ALTER TABLE " + table + " DROP CONSTRAINT IF EXISTS " + name).Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"be-job-portal/internal/domain"
+)
+
+func TestParseLegacySalary(t *testing.T) {
+	amount := func(v int64) *int64 { return &v }
+	tests := []struct {
+		text string
+		want domain.Salary
+	}{
+		{
+			text: "Rp 5.000.000 - 7.000.000 per month",
+			want: domain.Salary{Min: amount(5000000), Max: amount(7000000), Currency: "IDR", Period: domain.PayPeriodMonth},
+		},
+		{
+			text: "$80k-100k/year",
+			want: domain.Salary{Min: amount(80000), Max: amount(100000), Currency: "USD", Period: domain.PayPeriodYear},
+		},
+		{
+			text: "5-7 juta, negotiable",
+			want: domain.Salary{Min: amount(5000000), Max: amount(7000000), Currency: "IDR", Negotiable: true},
+		},
+		{
+			text: "IDR 8jt/bulan",
+			want: domain.Salary{Min: amount(8000000), Max: amount(8000000), Currency: "IDR", Period: domain.PayPeriodMonth},
+		},
+		{
+			text: "S$ 4,500 monthly",
+			want: domain.Salary{Min: amount(4500), Max: amount(4500), Currency: "SGD", Period: domain.PayPeriodMonth},
+		},
+		{
+			text: "EUR 25.50 per hour",
+			want: domain.Salary{Min: amount(26), Max: amount(26), Currency: "EUR", Period: domain.PayPeriodHour},
+		},
+		{
+			text: "£60,000 - £45,000 pa",
+			want: domain.Salary{Min: amount(45000), Max: amount(60000), Currency: "GBP", Period: domain.PayPeriodYear},
+		},
+		{
+			text: "1,500.50 USD",
+			want: domain.Salary{Min: amount(1501), Max: amount(1501), Currency: "USD"},
+		},
+		{
+			text: "Negotiable",
+			want: domain.Salary{Negotiable: true},
+		},
+		{
+			text: "Competitive",
+			want: domain.Salary{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := parseLegacySalary(tt.text)
+			if !equalAmount(got.Min, tt.want.Min) || !equalAmount(got.Max, tt.want.Max) ||
+				got.Currency != tt.want.Currency || got.Period != tt.want.Period || got.Negotiable != tt.want.Negotiable {
+				t.Fatalf("got %s, want %s", describeSalary(got), describeSalary(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseLegacyAmount(t *testing.T) {
+	tests := []struct {
+		number string
+		want   float64
+		ok     bool
+	}{
+		{number: "5.000.000", want: 5000000, ok: true},
+		{number: "1,500.50", want: 1500.5, ok: true},
+		{number: "1.500,50", want: 1500.5, ok: true},
+		{number: "5.5", want: 5.5, ok: true},
+		{number: "80", want: 80, ok: true},
+		{number: "100.", want: 100, ok: true},
+		{number: ".,", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			got, ok := parseLegacyAmount(tt.number)
+			if ok != tt.ok || (ok && got != tt.want) {
+				t.Fatalf("parseLegacyAmount(%q) = %v, %v; want %v, %v", tt.number, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func equalAmount(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func describeSalary(s domain.Salary) string {
+	amount := func(v *int64) string {
+		if v == nil {
+			return "nil"
+		}
+		return strconv.FormatInt(*v, 10)
+	}
+	return fmt.Sprintf("{min %s, max %s, %q, %q, negotiable %v}", amount(s.Min), amount(s.Max), s.Currency, s.Period, s.Negotiable)
+}
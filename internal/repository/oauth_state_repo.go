@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"be-job-portal/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type oauthStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthStateRepository(db *gorm.DB) domain.OAuthStateRepository {
+	return &oauthStateRepository{db}
+}
+
+// Create also sweeps states whose login was abandoned, keeping the table small.
+func (r *oauthStateRepository) Create(ctx context.Context, state *domain.OAuthState) error {
+	if err := r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&domain.OAuthState{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+func (r *oauthStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OAuthState, error) {
+	var states []domain.OAuthState
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) domain.OrganizationRepository {
+	return &organizationRepository{db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *domain.Organization, owner *domain.OrganizationMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		owner.OrganizationID = org.ID
+		return tx.Create(owner).Error
+	})
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	return r.db.WithContext(ctx).Save(org).Error
+}
+
+func (r *organizationRepository) GetMembership(ctx context.Context, userID uuid.UUID) (*domain.OrganizationMember, error) {
+	var member domain.OrganizationMember
+	err := r.db.WithContext(ctx).Preload("Organization").Where("user_id = ?", userID).First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *organizationRepository) GetMember(ctx context.Context, orgID, memberID uuid.UUID) (*domain.OrganizationMember, error) {
+	var member domain.OrganizationMember
+	err := r.db.WithContext(ctx).Where("id = ? AND organization_id = ?", memberID, orgID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID) ([]domain.OrganizationMember, error) {
+	var members []domain.OrganizationMember
+	err := r.db.WithContext(ctx).Preload("User").Where("organization_id = ?", orgID).Order("created_at ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *organizationRepository) CountOwners(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, domain.OrgRoleOwner).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *organizationRepository) CountMembers(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).Where("organization_id = ?", orgID).Count(&count).Error
+	return count, err
+}
+
+func (r *organizationRepository) HasJobs(ctx context.Context, orgID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&domain.Job{}).Where("organization_id = ?", orgID).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, memberID uuid.UUID, role string) error {
+	return r.db.WithContext(ctx).Model(&domain.OrganizationMember{}).Where("id = ?", memberID).Update("role", role).Error
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, memberID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.OrganizationMember{}, "id = ?", memberID).Error
+}
+
+func (r *organizationRepository) Dissolve(ctx context.Context, orgID, memberID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return dissolveOrganization(tx, orgID, memberID)
+	})
+}
+
+// dissolveOrganization removes the last member and soft-deletes the organization. Jobs
+// are kept for their applicants' history, but nobody can manage them any more, so open
+// ones are closed and pending invitations are dropped.
+func dissolveOrganization(tx *gorm.DB, orgID, memberID uuid.UUID) error {
+	if err := tx.Delete(&domain.OrganizationMember{}, "id = ? AND organization_id = ?", memberID, orgID).Error; err != nil {
+		return err
+	}
+	var remaining int64
+	if err := tx.Model(&domain.OrganizationMember{}).Where("organization_id = ?", orgID).Count(&remaining).Error; err != nil {
+		return err
+	}
+	if remaining > 0 {
+		// Someone joined in the meantime; leaving them an ownerless team would lock them out.
+		return domain.ErrBadRequest
+	}
+
+	err := tx.Model(&domain.Job{}).
+		Where("organization_id = ? AND status IN ?", orgID, []string{domain.JobStatusPublished, domain.JobStatusPaused}).
+		Updates(map[string]interface{}{"status": domain.JobStatusClosed, "closed_at": time.Now()}).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Where("organization_id = ? AND accepted_at IS NULL", orgID).Delete(&domain.OrganizationInvitation{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&domain.Organization{}, "id = ?", orgID).Error
+}
+
+func (r *organizationRepository) CreateInvitation(ctx context.Context, invitation *domain.OrganizationInvitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
+}
+
+func (r *organizationRepository) GetInvitationByHash(ctx context.Context, hash string) (*domain.OrganizationInvitation, error) {
+	var invitation domain.OrganizationInvitation
+	if err := r.db.WithContext(ctx).First(&invitation, "token_hash = ?", hash).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *organizationRepository) ListPendingInvitations(ctx context.Context, orgID uuid.UUID, now time.Time) ([]domain.OrganizationInvitation, error) {
+	var invitations []domain.OrganizationInvitation
+	err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND accepted_at IS NULL AND expires_at > ?", orgID, now).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *organizationRepository) DeleteInvitation(ctx context.Context, orgID, invitationID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND organization_id = ? AND accepted_at IS NULL", invitationID, orgID).
+		Delete(&domain.OrganizationInvitation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation claims the invitation with a conditional update so it can only be used once.
+func (r *organizationRepository) AcceptInvitation(ctx context.Context, invitationID uuid.UUID, member, leaving *domain.OrganizationMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitationID).
+			Update("accepted_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrUnauthorized
+		}
+		if leaving != nil {
+			if err := dissolveOrganization(tx, leaving.OrganizationID, leaving.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(member).Error
+	})
+}
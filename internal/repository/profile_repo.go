@@ -60,9 +60,9 @@ func (r *profileRepository) UpdateSeekerProfile(ctx context.Context, profile *do
 	})
 }
 
-func (r *profileRepository) GetCompanyProfile(ctx context.Context, userID uuid.UUID) (*domain.CompanyProfile, error) {
+func (r *profileRepository) GetCompanyProfile(ctx context.Context, orgID uuid.UUID) (*domain.CompanyProfile, error) {
 	var profile domain.CompanyProfile
-	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error
+	err := r.db.WithContext(ctx).Where("organization_id = ?", orgID).First(&profile).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -74,7 +74,7 @@ func (r *profileRepository) GetCompanyProfile(ctx context.Context, userID uuid.U
 
 func (r *profileRepository) UpdateCompanyProfile(ctx context.Context, profile *domain.CompanyProfile) error {
 	var existing domain.CompanyProfile
-	err := r.db.WithContext(ctx).Where("user_id = ?", profile.UserID).First(&existing).Error
+	err := r.db.WithContext(ctx).Where("organization_id = ?", profile.OrganizationID).First(&existing).Error
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -84,5 +84,7 @@ func (r *profileRepository) UpdateCompanyProfile(ctx context.Context, profile *d
 	}
 
 	profile.ID = existing.ID
+	profile.UserID = existing.UserID
+	profile.CreatedAt = existing.CreatedAt
 	return r.db.WithContext(ctx).Save(profile).Error
 }
@@ -3,6 +3,8 @@ package repository
 import (
 	"be-job-portal/internal/domain"
 	"context"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -31,3 +33,80 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 	err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error
 	return &user, err
 }
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("password", hashedPassword).Error
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("email_verified_at", verifiedAt).Error
+}
+
+// SetTwoFactor stores the TOTP secret; a nil enabledAt keeps it pending until the user confirms a code.
+func (r *userRepository) SetTwoFactor(ctx context.Context, id uuid.UUID, secret string, enabledAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"totp_secret":           secret,
+		"totp_last_step":        0,
+		"two_factor_enabled_at": enabledAt,
+	}).Error
+}
+
+// AdvanceTOTPStep records the last accepted time step so a code cannot be replayed.
+func (r *userRepository) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) error {
+	result := r.db.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
+
+func (r *userRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.User{})
+	if filter.Query != "" {
+		query = query.Where("email ILIKE ?", "%"+escapeLike(filter.Query)+"%")
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			query = query.Where("suspended_at IS NOT NULL")
+		} else {
+			query = query.Where("suspended_at IS NULL")
+		}
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	offset := (filter.Page - 1) * filter.Limit
+	err := query.Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, totalCount, nil
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r *userRepository) SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("suspended_at", suspendedAt).Error
+}
+
+// escapeLike stops user input from acting as LIKE wildcards.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"be-job-portal/internal/config"
+	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type adminUsecase struct {
+	userRepo    domain.UserRepository
+	sessionRepo domain.SessionRepository
+	keys        *utils.KeySet
+	audit       domain.AuditLogger
+	cfg         config.Config
+}
+
+func NewAdminUsecase(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, keys *utils.KeySet, audit domain.AuditLogger, cfg config.Config) domain.AdminUsecase {
+	return &adminUsecase{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		keys:        keys,
+		audit:       audit,
+		cfg:         cfg,
+	}
+}
+
+func (u *adminUsecase) ListUsers(ctx context.Context, filter domain.UserFilter) (*domain.PaginatedUsersResponse, error) {
+	users, totalCount, err := u.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(totalCount) / filter.Limit
+	if int(totalCount)%filter.Limit != 0 {
+		totalPages++
+	}
+
+	return &domain.PaginatedUsersResponse{
+		Users: users,
+		Pagination: domain.PaginationMeta{
+			CurrentPage:  filter.Page,
+			TotalPages:   totalPages,
+			TotalItems:   totalCount,
+			ItemsPerPage: filter.Limit,
+			HasNext:      filter.Page < totalPages,
+			HasPrev:      filter.Page > 1,
+		},
+	}, nil
+}
+
+func (u *adminUsecase) GetUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, err := u.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangeRole signs the user out everywhere so no access token keeps the old role.
+// Admins cannot change their own role, which keeps at least one admin around.
+func (u *adminUsecase) ChangeRole(ctx context.Context, adminID, userID uuid.UUID, role string) (*domain.User, error) {
+	if adminID == userID {
+		return nil, domain.ErrBadRequest
+	}
+
+	user, err := u.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := u.userRepo.UpdateRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	if err := u.sessionRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditRoleChanged,
+		ActorID:    &adminID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+		Changes:    map[string]domain.AuditChange{"role": {Before: user.Role, After: role}},
+	})
+
+	user.Role = role
+	return user, nil
+}
+
+func (u *adminUsecase) SuspendUser(ctx context.Context, adminID, userID uuid.UUID) (*domain.User, error) {
+	if adminID == userID {
+		return nil, domain.ErrBadRequest
+	}
+
+	user, err := u.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return user, nil
+	}
+
+	now := time.Now()
+	if err := u.userRepo.SetSuspended(ctx, userID, &now); err != nil {
+		return nil, err
+	}
+	if err := u.sessionRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditUserSuspended,
+		ActorID:    &adminID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
+
+	user.SuspendedAt = &now
+	return user, nil
+}
+
+func (u *adminUsecase) UnsuspendUser(ctx context.Context, adminID, userID uuid.UUID) (*domain.User, error) {
+	user, err := u.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.userRepo.SetSuspended(ctx, userID, nil); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditUserUnsuspended,
+		ActorID:    &adminID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
+
+	user.SuspendedAt = nil
+	return user, nil
+}
+
+func (u *adminUsecase) ForceLogout(ctx context.Context, adminID, userID uuid.UUID) error {
+	if _, err := u.GetUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := u.sessionRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditForcedLogout,
+		ActorID:    &adminID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &userID,
+	})
+	return nil
+}
+
+// Impersonate lets support staff see exactly what a user sees. The session is tied to
+// the admin, expires after ImpersonationTTL and has no usable refresh token; other
+// admins cannot be impersonated.
+func (u *adminUsecase) Impersonate(ctx context.Context, adminID, userID uuid.UUID, client domain.ClientInfo) (*domain.ImpersonationToken, error) {
+	if adminID == userID {
+		return nil, domain.ErrBadRequest
+	}
+
+	user, err := u.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == domain.RoleAdmin {
+		return nil, domain.ErrForbidden
+	}
+	if user.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	// The refresh token is never handed out, so the session ends with its access token.
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        userAgent,
+		IP:               client.IP,
+		LastSeenAt:       &now,
+		ExpiresAt:        now.Add(u.cfg.ImpersonationTTL),
+		ImpersonatorID:   &adminID,
+	}
+	if err := u.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := utils.GenerateImpersonationToken(u.keys, user.ID, session.ID, user.Role, adminID, u.cfg.ImpersonationTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditImpersonationStarted,
+		ActorID:    &adminID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+		Metadata:   map[string]string{"session_id": session.ID.String()},
+	})
+	if err != nil {
+		// An impersonation that cannot be audited must not be usable.
+		if revokeErr := u.sessionRepo.Revoke(ctx, session.ID); revokeErr != nil {
+			return nil, revokeErr
+		}
+		return nil, err
+	}
+
+	return &domain.ImpersonationToken{
+		AccessToken: accessToken,
+		SessionID:   session.ID,
+		ExpiresAt:   expiresAt,
+		User:        user,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiKeyTouchInterval limits how often last-used is written, as for sessions.
+const apiKeyTouchInterval = time.Minute
+
+// apiKeyDisplayLength is how much of the key is kept in clear so users can tell keys apart.
+const apiKeyDisplayLength = 12
+
+type apiKeyUsecase struct {
+	apiKeyRepo domain.APIKeyRepository
+	orgRepo    domain.OrganizationRepository
+}
+
+func NewAPIKeyUsecase(apiKeyRepo domain.APIKeyRepository, orgRepo domain.OrganizationRepository) domain.APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		orgRepo:    orgRepo,
+	}
+}
+
+func (u *apiKeyUsecase) CreateKey(ctx context.Context, actor domain.Actor, name string, scopes []string, expiresAt *time.Time) (*domain.CreatedAPIKey, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionCreateAPIKey, nil); err != nil {
+		return nil, err
+	}
+
+	if len(scopes) == 0 {
+		return nil, domain.ErrBadRequest
+	}
+	seen := make(map[string]bool, len(scopes))
+	var granted []string
+	for _, scope := range scopes {
+		if !domain.IsValidScope(scope) {
+			return nil, domain.ErrBadRequest
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			granted = append(granted, scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, domain.ErrBadRequest
+	}
+
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	raw := domain.APIKeyPrefix + secret
+
+	key := domain.APIKey{
+		OrganizationID: actor.Membership.OrganizationID,
+		UserID:         actor.UserID,
+		Name:           name,
+		Prefix:         raw[:apiKeyDisplayLength],
+		KeyHash:        utils.HashToken(raw),
+		Scopes:         granted,
+		ExpiresAt:      expiresAt,
+	}
+	if err := u.apiKeyRepo.Create(ctx, &key); err != nil {
+		return nil, err
+	}
+	return &domain.CreatedAPIKey{APIKey: key, Key: raw}, nil
+}
+
+// ListKeys returns every key of the organization to owners and admins, and only their
+// own keys to other members.
+func (u *apiKeyUsecase) ListKeys(ctx context.Context, actor domain.Actor) ([]domain.APIKey, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Membership == nil {
+		return []domain.APIKey{}, nil
+	}
+
+	orgID := actor.Membership.OrganizationID
+	if domain.Authorize(actor, domain.ActionManageOrganization, nil) == nil {
+		return u.apiKeyRepo.ListByOrganization(ctx, orgID)
+	}
+	return u.apiKeyRepo.ListByUser(ctx, orgID, actor.UserID)
+}
+
+func (u *apiKeyUsecase) RevokeKey(ctx context.Context, actor domain.Actor, id uuid.UUID) error {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return err
+	}
+	if actor.Membership == nil {
+		return domain.ErrNotFound
+	}
+
+	key, err := u.apiKeyRepo.GetByID(ctx, actor.Membership.OrganizationID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	if err := domain.Authorize(actor, domain.ActionManageAPIKey, key); err != nil {
+		return err
+	}
+	return u.apiKeyRepo.Revoke(ctx, key.ID)
+}
+
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	key, err := u.apiKeyRepo.GetByHash(ctx, utils.HashToken(rawKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if !key.IsActive(now) || key.User == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	if key.User.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	// A key stops working once its creator leaves the organization it was issued for.
+	member, err := u.orgRepo.GetMembership(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.OrganizationID != key.OrganizationID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := u.apiKeyRepo.Touch(ctx, key.ID, now); err != nil {
+			log.Printf("failed to update api key last used: %v", err)
+		}
+	}
+	return key, nil
+}
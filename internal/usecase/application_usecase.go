@@ -3,58 +3,116 @@ package usecase
 import (
 	"be-job-portal/internal/domain"
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type applicationUsecase struct {
-	appRepo domain.ApplicationRepository
-	jobRepo domain.JobRepository
+	appRepo     domain.ApplicationRepository
+	jobRepo     domain.JobRepository
+	orgRepo     domain.OrganizationRepository
+	profileRepo domain.ProfileRepository
+	audit       domain.AuditLogger
 }
 
-func NewApplicationUsecase(appRepo domain.ApplicationRepository, jobRepo domain.JobRepository) domain.ApplicationUsecase {
-	return &applicationUsecase{appRepo, jobRepo}
+func NewApplicationUsecase(appRepo domain.ApplicationRepository, jobRepo domain.JobRepository, orgRepo domain.OrganizationRepository, profileRepo domain.ProfileRepository, audit domain.AuditLogger) domain.ApplicationUsecase {
+	return &applicationUsecase{appRepo, jobRepo, orgRepo, profileRepo, audit}
 }
 
-func (u *applicationUsecase) ApplyJob(ctx context.Context, jobID, seekerID uuid.UUID, resumeURL, coverLetter, linkedInURL, portfolioURL string) error {
-	_, err := u.jobRepo.GetByID(ctx, jobID)
+func (u *applicationUsecase) ApplyJob(ctx context.Context, actor domain.Actor, jobID uuid.UUID, resumeURL, coverLetter, linkedInURL, portfolioURL string, answers []domain.ScreeningAnswer) error {
+	if err := domain.Authorize(actor, domain.ActionApplyJob, nil); err != nil {
+		return err
+	}
+
+	job, err := u.getJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if !job.AcceptsApplications(time.Now()) {
+		// Drafts and archived jobs are not public, so they look like missing jobs.
+		if !job.IsPublic() {
+			return domain.ErrNotFound
+		}
+		return domain.ErrJobNotOpen
+	}
+
+	// The candidate applies to the posting as it reads right now, so the answers are
+	// screened against that revision's questions, which are the ones they saw.
+	revision, err := u.jobRepo.GetLatestRevision(ctx, jobID)
 	if err != nil {
 		return err
 	}
 
+	screening, err := domain.EvaluateScreening(revision.Content.Questions, answers)
+	if err != nil {
+		return err
+	}
+	// Failing a reject rule still stores the application, so the recruiter can see why.
+	status := domain.StatusPending
+	if screening.Reject {
+		status = domain.StatusRejected
+	}
+
+	// Score the seeker against the revision they applied to; a missing profile scores
+	// on the requirements alone.
+	profile, err := u.profileRepo.GetSeekerProfile(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+	score, match := domain.ScoreMatch(revision.Content, profile, time.Now())
+
 	app := &domain.Application{
-		JobID:        jobID,
-		SeekerID:     seekerID,
-		Status:       "PENDING",
-		ResumeURL:    resumeURL,
-		CoverLetter:  coverLetter,
-		LinkedInURL:  linkedInURL,
-		PortfolioURL: portfolioURL,
+		JobID:             jobID,
+		JobRevisionID:     &revision.ID,
+		SeekerID:          actor.UserID,
+		Status:            status,
+		ResumeURL:         resumeURL,
+		CoverLetter:       coverLetter,
+		LinkedInURL:       linkedInURL,
+		PortfolioURL:      portfolioURL,
+		Answers:           answers,
+		Flagged:           screening.Flag,
+		FailedQuestionIDs: screening.FailedQuestionIDs,
+		MatchScore:        &score,
+		Match:             &match,
 	}
 	return u.appRepo.Create(ctx, app)
 }
 
-func (u *applicationUsecase) ListApplications(ctx context.Context, userID uuid.UUID, role string) ([]domain.Application, error) {
-	if role == "SEEKER" {
-		return u.appRepo.GetBySeekerID(ctx, userID)
+func (u *applicationUsecase) ListApplications(ctx context.Context, actor domain.Actor) ([]domain.Application, error) {
+	if err := domain.Authorize(actor, domain.ActionListOwnApplications, nil); err != nil {
+		return nil, err
 	}
-
-	return []domain.Application{}, nil
+	return u.appRepo.GetBySeekerID(ctx, actor.UserID)
 }
 
-func (u *applicationUsecase) ListJobApplicants(ctx context.Context, jobID, recruiterID uuid.UUID) ([]domain.Application, error) {
-	job, err := u.jobRepo.GetByID(ctx, jobID)
+func (u *applicationUsecase) ListJobApplicants(ctx context.Context, actor domain.Actor, jobID uuid.UUID, sort string) ([]domain.Application, error) {
+	if sort == "" {
+		sort = domain.ApplicantSortNewest
+	}
+	if !domain.IsValidApplicantSort(sort) {
+		return nil, domain.ErrBadRequest
+	}
+
+	job, err := u.getJob(ctx, jobID)
 	if err != nil {
 		return nil, err
 	}
-	if job.RecruiterID != recruiterID {
-		return nil, domain.ErrUnauthorized
+	actor, err = withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionViewApplicants, job); err != nil {
+		return nil, err
 	}
 
-	return u.appRepo.GetByJobID(ctx, jobID)
+	return u.appRepo.GetByJobID(ctx, jobID, sort)
 }
 
-func (u *applicationUsecase) UpdateStatus(ctx context.Context, appID, recruiterID uuid.UUID, status string) error {
+func (u *applicationUsecase) UpdateStatus(ctx context.Context, actor domain.Actor, appID uuid.UUID, status string) error {
 	validStatuses := map[string]bool{
 		domain.StatusPending:  true,
 		domain.StatusProcess:  true,
@@ -67,22 +125,63 @@ func (u *applicationUsecase) UpdateStatus(ctx context.Context, appID, recruiterI
 	}
 
 	app, err := u.appRepo.GetByID(ctx, appID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	job, err := u.getJob(ctx, app.JobID)
 	if err != nil {
 		return err
 	}
 
-	job, err := u.jobRepo.GetByID(ctx, app.JobID)
+	actor, err = withMembership(ctx, u.orgRepo, actor)
 	if err != nil {
 		return err
 	}
+	if err := domain.Authorize(actor, domain.ActionUpdateApplicationStatus, job); err != nil {
+		return err
+	}
 
-	if job.RecruiterID != recruiterID {
-		return domain.ErrUnauthorized
+	if err := u.appRepo.UpdateStatus(ctx, appID, status); err != nil {
+		return err
 	}
 
-	return u.appRepo.UpdateStatus(ctx, appID, status)
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditApplicationStatus,
+		ActorID:    &actor.UserID,
+		TargetType: domain.AuditTargetApplication,
+		TargetID:   &app.ID,
+		Changes:    map[string]domain.AuditChange{"status": {Before: app.Status, After: status}},
+		Metadata:   map[string]string{"job_id": job.ID.String()},
+	})
+	return nil
 }
 
-func (u *applicationUsecase) GetDashboardStats(ctx context.Context, recruiterID uuid.UUID) (*domain.DashboardStats, error) {
-	return u.appRepo.GetDashboardStats(ctx, recruiterID)
+func (u *applicationUsecase) GetDashboardStats(ctx context.Context, actor domain.Actor) (*domain.DashboardStats, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	// A recruiter who has not posted yet has no organization and nothing to report.
+	if actor.Role == domain.RoleRecruiter && actor.Membership == nil {
+		return &domain.DashboardStats{StatusDistribution: map[string]int{}}, nil
+	}
+	if err := domain.Authorize(actor, domain.ActionViewDashboard, nil); err != nil {
+		return nil, err
+	}
+	return u.appRepo.GetDashboardStats(ctx, actor.Membership.OrganizationID)
+}
+
+func (u *applicationUsecase) getJob(ctx context.Context, jobID uuid.UUID) (*domain.Job, error) {
+	job, err := u.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return job, nil
 }
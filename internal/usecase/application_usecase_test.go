@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestApplyJobScreensAgainstTheLatestRevision(t *testing.T) {
+	ctx := context.Background()
+	yes, no := true, false
+	question := func(id uuid.UUID, required bool) domain.ScreeningQuestion {
+		return domain.ScreeningQuestion{
+			ID:       id,
+			Type:     domain.QuestionYesNo,
+			Prompt:   "Do you have a work permit?",
+			Required: required,
+			Knockout: &domain.KnockoutRule{Action: domain.KnockoutReject, Equals: &yes},
+		}
+	}
+	stale, current := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		answers []domain.ScreeningAnswer
+		want    string
+	}{
+		{
+			name:    "passing the revision's knockout",
+			answers: []domain.ScreeningAnswer{{QuestionID: current, Boolean: &yes}},
+			want:    domain.StatusPending,
+		},
+		{
+			name:    "failing the revision's knockout",
+			answers: []domain.ScreeningAnswer{{QuestionID: current, Boolean: &no}},
+			want:    domain.StatusRejected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The job row still carries a question the latest revision replaced; the
+			// candidate only ever saw the revision.
+			job := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPublished, Questions: []domain.ScreeningQuestion{question(stale, true)}}
+			revisionJob := *job
+			revisionJob.Questions = []domain.ScreeningQuestion{question(current, true)}
+			jobs := newFakeJobRepo(job)
+			jobs.addRevision(job.ID, job.Content())
+			latest := jobs.addRevision(job.ID, revisionJob.Content())
+			apps := &fakeApplicationRepo{}
+			u := NewApplicationUsecase(apps, jobs, newFakeOrgRepo(), &fakeProfileRepo{}, &fakeAuditLogger{})
+
+			seeker := domain.Actor{UserID: uuid.New(), Role: domain.RoleSeeker}
+			if err := u.ApplyJob(ctx, seeker, job.ID, "https://example.com/cv.pdf", "", "", "", tt.answers); err != nil {
+				t.Fatalf("ApplyJob: %v", err)
+			}
+			if len(apps.created) != 1 {
+				t.Fatalf("created %d applications, want 1", len(apps.created))
+			}
+			app := apps.created[0]
+			if app.Status != tt.want {
+				t.Fatalf("status = %s, want %s", app.Status, tt.want)
+			}
+			if app.JobRevisionID == nil || *app.JobRevisionID != latest.ID {
+				t.Fatalf("application points at revision %v, want the latest", app.JobRevisionID)
+			}
+		})
+	}
+}
+
+func TestApplyJobRejectsAnswersToReplacedQuestions(t *testing.T) {
+	ctx := context.Background()
+	yes := true
+	stale := domain.ScreeningQuestion{ID: uuid.New(), Type: domain.QuestionYesNo, Prompt: "Old question?"}
+	job := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPublished, Questions: []domain.ScreeningQuestion{stale}}
+	revisionJob := *job
+	revisionJob.Questions = nil
+	jobs := newFakeJobRepo(job)
+	jobs.addRevision(job.ID, job.Content())
+	jobs.addRevision(job.ID, revisionJob.Content())
+	apps := &fakeApplicationRepo{}
+	u := NewApplicationUsecase(apps, jobs, newFakeOrgRepo(), &fakeProfileRepo{}, &fakeAuditLogger{})
+
+	seeker := domain.Actor{UserID: uuid.New(), Role: domain.RoleSeeker}
+	err := u.ApplyJob(ctx, seeker, job.ID, "https://example.com/cv.pdf", "", "", "", []domain.ScreeningAnswer{{QuestionID: stale.ID, Boolean: &yes}})
+	if err == nil {
+		t.Fatal("ApplyJob accepted an answer to a question the latest revision no longer asks")
+	}
+	if len(apps.created) != 0 {
+		t.Fatalf("created %d applications, want none", len(apps.created))
+	}
+}
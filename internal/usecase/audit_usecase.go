@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+
+	"be-job-portal/internal/domain"
+)
+
+type auditUsecase struct {
+	auditRepo domain.AuditRepository
+}
+
+func NewAuditUsecase(auditRepo domain.AuditRepository) domain.AuditUsecase {
+	return &auditUsecase{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record appends event, taking the request ID, IP and impersonator from ctx when the
+// caller did not set them.
+func (u *auditUsecase) Record(ctx context.Context, event domain.AuditEvent) error {
+	info := domain.RequestInfoFrom(ctx)
+	if event.RequestID == "" {
+		event.RequestID = info.RequestID
+	}
+	if event.IP == "" {
+		event.IP = info.IP
+	}
+	if event.ImpersonatorID == nil {
+		event.ImpersonatorID = info.ImpersonatorID
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	return u.auditRepo.Create(ctx, &event)
+}
+
+func (u *auditUsecase) ListEvents(ctx context.Context, filter domain.AuditFilter) (*domain.PaginatedAuditEventsResponse, error) {
+	events, totalCount, err := u.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(totalCount) / filter.Limit
+	if int(totalCount)%filter.Limit != 0 {
+		totalPages++
+	}
+
+	return &domain.PaginatedAuditEventsResponse{
+		Events: events,
+		Pagination: domain.PaginationMeta{
+			CurrentPage:  filter.Page,
+			TotalPages:   totalPages,
+			TotalItems:   totalCount,
+			ItemsPerPage: filter.Limit,
+			HasNext:      filter.Page < totalPages,
+			HasPrev:      filter.Page > 1,
+		},
+	}, nil
+}
+
+// recordAudit writes an event for an action that has already happened. A failed write is
+// logged rather than undoing the action.
+func recordAudit(ctx context.Context, logger domain.AuditLogger, event domain.AuditEvent) {
+	if err := logger.Record(ctx, event); err != nil {
+		log.Printf("failed to record audit event %s: %v", event.Action, err)
+	}
+}
+
+// auditDiff compares the named JSON fields of two values of the same type and returns
+// the ones that changed, or nil if none did.
+func auditDiff(before, after interface{}, fields ...string) map[string]domain.AuditChange {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil
+	}
+
+	var changes map[string]domain.AuditChange
+	for _, field := range fields {
+		if reflect.DeepEqual(beforeFields[field], afterFields[field]) {
+			continue
+		}
+		if changes == nil {
+			changes = make(map[string]domain.AuditChange)
+		}
+		changes[field] = domain.AuditChange{Before: beforeFields[field], After: afterFields[field]}
+	}
+	return changes
+}
+
+func jsonFields(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(raw, &fields)
+	return fields, err
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"be-job-portal/internal/domain"
+)
+
+func TestAuditDiff(t *testing.T) {
+	low, high := int64(5000000), int64(7000000)
+	expiry := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	base := domain.JobContent{
+		Title:          "Go Engineer",
+		Salary:         domain.Salary{Min: &low, Max: &high, Currency: "IDR", Period: domain.PayPeriodMonth},
+		Benefits:       []string{"Remote"},
+		RequiredSkills: []string{"go"},
+	}
+
+	tests := []struct {
+		name   string
+		change func(*domain.JobContent)
+		fields []string
+		want   map[string]domain.AuditChange
+	}{
+		{
+			name:   "nothing changed",
+			change: func(c *domain.JobContent) {},
+			fields: domain.JobContentFields,
+		},
+		{
+			name:   "same slice contents in a new slice",
+			change: func(c *domain.JobContent) { c.Benefits = []string{"Remote"} },
+			fields: domain.JobContentFields,
+		},
+		{
+			name:   "scalar field",
+			change: func(c *domain.JobContent) { c.Title = "Senior Go Engineer" },
+			fields: domain.JobContentFields,
+			want:   map[string]domain.AuditChange{"title": {Before: "Go Engineer", After: "Senior Go Engineer"}},
+		},
+		{
+			name:   "nested struct is compared whole",
+			change: func(c *domain.JobContent) { c.Salary.Negotiable = true },
+			fields: domain.JobContentFields,
+			want: map[string]domain.AuditChange{"salary": {
+				Before: map[string]interface{}{"min": float64(low), "max": float64(high), "currency": "IDR", "period": "month", "negotiable": false},
+				After:  map[string]interface{}{"min": float64(low), "max": float64(high), "currency": "IDR", "period": "month", "negotiable": true},
+			}},
+		},
+		{
+			name:   "nil slice against a set one",
+			change: func(c *domain.JobContent) { c.PreferredSkills = []string{"sql"} },
+			fields: domain.JobContentFields,
+			want:   map[string]domain.AuditChange{"preferred_skills": {Before: nil, After: []interface{}{"sql"}}},
+		},
+		{
+			name:   "time set",
+			change: func(c *domain.JobContent) { c.ExpiresAt = &expiry },
+			fields: domain.JobContentFields,
+			want:   map[string]domain.AuditChange{"expires_at": {Before: nil, After: "2026-03-01T00:00:00Z"}},
+		},
+		{
+			name: "only the listed fields",
+			change: func(c *domain.JobContent) {
+				c.Title = "Senior Go Engineer"
+				c.Category = "Engineering"
+			},
+			fields: []string{"category"},
+			want:   map[string]domain.AuditChange{"category": {Before: "", After: "Engineering"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base
+			after.Benefits = append([]string(nil), base.Benefits...)
+			tt.change(&after)
+
+			got := auditDiff(base, after, tt.fields...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("auditDiff = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,39 +2,70 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	"fmt"
-	"net/http"
+	"log"
+	"strings"
+	"time"
 
 	"be-job-portal/internal/config"
 	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/oidc"
+	"be-job-portal/pkg/totp"
 	"be-job-portal/pkg/utils"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
+	"gorm.io/gorm"
 )
 
 type authUsecase struct {
-	userRepo domain.UserRepository
-	cfg      config.Config
-	oauthCfg *oauth2.Config
-}
+	userRepo     domain.UserRepository
+	sessionRepo  domain.SessionRepository
+	tokenRepo    domain.UserTokenRepository
+	recoveryRepo domain.RecoveryCodeRepository
+	stateRepo    domain.OAuthStateRepository
+	identityRepo domain.UserIdentityRepository
+	mailer       domain.MailSender
+	providers    *oidc.Registry
+	keys         *utils.KeySet
+	audit        domain.AuditLogger
+	cfg          config.Config
 
-func NewAuthUsecase(userRepo domain.UserRepository, cfg config.Config) domain.AuthUsecase {
-	oauthCfg := &oauth2.Config{
-		ClientID:     cfg.GoogleClientID,
-		ClientSecret: cfg.GoogleClientSecret,
-		RedirectURL:  cfg.GoogleRedirectURL,
-		Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"},
-		Endpoint:     google.Endpoint,
-	}
+	accountThrottle *loginThrottle
+	ipThrottle      *loginThrottle
+}
 
+func NewAuthUsecase(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, tokenRepo domain.UserTokenRepository, recoveryRepo domain.RecoveryCodeRepository, attemptRepo domain.LoginAttemptRepository, stateRepo domain.OAuthStateRepository, identityRepo domain.UserIdentityRepository, mailer domain.MailSender, providers *oidc.Registry, keys *utils.KeySet, audit domain.AuditLogger, cfg config.Config) domain.AuthUsecase {
 	return &authUsecase{
-		userRepo: userRepo,
-		cfg:      cfg,
-		oauthCfg: oauthCfg,
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		tokenRepo:    tokenRepo,
+		recoveryRepo: recoveryRepo,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		mailer:       mailer,
+		providers:    providers,
+		keys:         keys,
+		audit:        audit,
+		cfg:          cfg,
+		accountThrottle: &loginThrottle{
+			store:       attemptRepo,
+			maxFailures: cfg.LoginMaxFailures,
+			lockout:     cfg.LoginLockout,
+			maxLockout:  cfg.LoginMaxLockout,
+			window:      cfg.LoginFailureWindow,
+		},
+		ipThrottle: &loginThrottle{
+			store:       attemptRepo,
+			maxFailures: cfg.LoginMaxFailuresIP,
+			lockout:     cfg.LoginLockout,
+			maxLockout:  cfg.LoginMaxLockout,
+			window:      cfg.LoginFailureWindow,
+		},
 	}
 }
 
@@ -51,57 +82,875 @@ func (u *authUsecase) Register(ctx context.Context, email, password, role string
 		Provider: "local",
 	}
 
-	return u.userRepo.Create(ctx, user)
+	if err := u.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditRegister,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+		Metadata:   map[string]string{"role": role},
+	})
+
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("failed to send verification email: %v", err)
+	}
+
+	return nil
 }
 
-func (u *authUsecase) Login(ctx context.Context, email, password string) (string, error) {
+func (u *authUsecase) Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	accountKey := "email:" + strings.ToLower(email)
+	ipKey := "ip:" + client.IP
+	if err := u.checkThrottles(ctx, accountKey, ipKey); err != nil {
+		return nil, err
+	}
+
 	user, err := u.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		u.recordFailure(ctx, accountKey, ipKey)
+		recordAudit(ctx, u.audit, domain.AuditEvent{
+			Action:   domain.AuditLoginFailed,
+			Metadata: map[string]string{"email": email, "reason": "unknown_email"},
+		})
+		return nil, errors.New("invalid credentials")
 	}
 
-	if user.Provider != "local" {
-		return "", fmt.Errorf("please login with %s", user.Provider)
+	if user.Password == "" {
+		return nil, fmt.Errorf("please login with %s", user.Provider)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		u.recordFailure(ctx, accountKey, ipKey)
+		recordAudit(ctx, u.audit, domain.AuditEvent{
+			Action:     domain.AuditLoginFailed,
+			ActorID:    &user.ID,
+			TargetType: domain.AuditTargetUser,
+			TargetID:   &user.ID,
+			Metadata:   map[string]string{"reason": "wrong_password"},
+		})
+		return nil, errors.New("invalid credentials")
+	}
+
+	if err := u.accountThrottle.reset(ctx, accountKey); err != nil {
+		log.Printf("failed to reset login throttle: %v", err)
 	}
 
-	return utils.GenerateToken(user.ID, user.Role, u.cfg.JWTSecret)
+	return u.completeLogin(ctx, user, client)
 }
 
-func (u *authUsecase) GoogleLogin(ctx context.Context, code string) (string, error) {
-	token, err := u.oauthCfg.Exchange(ctx, code)
+func (u *authUsecase) OAuthLoginURL(ctx context.Context, providerName string) (*domain.OAuthRedirect, error) {
+	return u.beginOAuth(ctx, providerName, nil)
+}
+
+// StartIdentityLink begins a provider login on behalf of a signed-in user. The callback
+// attaches the provider account to that user instead of signing anyone in.
+func (u *authUsecase) StartIdentityLink(ctx context.Context, userID uuid.UUID, providerName string) (*domain.OAuthRedirect, error) {
+	return u.beginOAuth(ctx, providerName, &userID)
+}
+
+func (u *authUsecase) OAuthLogin(ctx context.Context, providerName, code, state string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	provider, ok := u.providers.Get(providerName)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	pending, err := u.stateRepo.Consume(ctx, utils.HashToken(state))
 	if err != nil {
-		return "", err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if pending.Provider != provider.Name() || time.Now().After(pending.ExpiresAt) {
+		return nil, domain.ErrUnauthorized
 	}
 
-	resp, err := http.Get("https://www.googleapis.com/oauth2/v2/userinfo?access_token=" + token.AccessToken)
+	identity, err := provider.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var googleUser struct {
-		Email string `json:"email"`
+	if pending.LinkUserID != nil {
+		linked, err := u.linkIdentity(ctx, *pending.LinkUserID, provider.Name(), identity)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{LinkedIdentity: linked}, nil
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&googleUser); err != nil {
-		return "", err
+
+	user, err := u.resolveIdentity(ctx, provider.Name(), identity)
+	if err != nil {
+		return nil, err
 	}
 
-	user, err := u.userRepo.GetByEmail(ctx, googleUser.Email)
+	return u.completeLogin(ctx, user, client)
+}
+
+func (u *authUsecase) ListIdentities(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	return u.identityRepo.GetByUserID(ctx, userID)
+}
+
+// UnlinkIdentity refuses to remove the last way the user can sign in.
+func (u *authUsecase) UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error {
+	user, err := u.userRepo.GetByID(ctx, userID)
 	if err != nil {
+		return err
+	}
+	identities, err := u.identityRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == "" && len(identities) <= 1 {
+		return domain.ErrBadRequest
+	}
+
+	if err := u.identityRepo.Delete(ctx, identityID, userID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditIdentityUnlinked,
+		ActorID:    &userID,
+		TargetType: domain.AuditTargetIdentity,
+		TargetID:   &identityID,
+	})
+	return nil
+}
+
+func (u *authUsecase) beginOAuth(ctx context.Context, providerName string, linkUserID *uuid.UUID) (*domain.OAuthRedirect, error) {
+	provider, ok := u.providers.Get(providerName)
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	expiresAt := time.Now().Add(u.cfg.OIDCStateTTL)
+
+	err = u.stateRepo.Create(ctx, &domain.OAuthState{
+		StateHash:    utils.HashToken(state),
+		Provider:     provider.Name(),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OAuthRedirect{URL: authURL, State: state, ExpiresAt: expiresAt}, nil
+}
+
+// resolveIdentity finds the user behind a provider account, creating one for new emails.
+// A matching email alone is never enough to sign into an existing account: the owner
+// has to link the provider while signed in. The only exception is an account this same
+// provider created before identities were tracked, which is adopted on first login, and
+// only when the provider vouches for the address.
+func (u *authUsecase) resolveIdentity(ctx context.Context, providerName string, identity *oidc.Identity) (*domain.User, error) {
+	existing, err := u.identityRepo.GetByProviderSubject(ctx, providerName, identity.Subject)
+	if err == nil {
+		return u.userRepo.GetByID(ctx, existing.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" {
+		return nil, fmt.Errorf("%s did not return an email address", providerName)
+	}
+
+	user, err := u.userRepo.GetByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if user.Provider != providerName || !identity.EmailVerified {
+			return nil, domain.ErrConflict
+		}
+		identities, err := u.identityRepo.GetByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, linked := range identities {
+			if linked.Provider == providerName {
+				return nil, domain.ErrConflict
+			}
+		}
+
+	case errors.Is(err, gorm.ErrRecordNotFound):
 		user = &domain.User{
-			Email:    googleUser.Email,
-			Role:     "SEEKER",
-			Provider: "google",
+			Email:    identity.Email,
+			Role:     domain.RoleSeeker,
+			Provider: providerName,
+		}
+		if identity.EmailVerified {
+			verifiedAt := time.Now()
+			user.EmailVerifiedAt = &verifiedAt
 		}
 		if err := u.userRepo.Create(ctx, user); err != nil {
-			return "", err
+			return nil, err
+		}
+
+	default:
+		return nil, err
+	}
+
+	err = u.identityRepo.Create(ctx, &domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *authUsecase) linkIdentity(ctx context.Context, userID uuid.UUID, providerName string, identity *oidc.Identity) (*domain.UserIdentity, error) {
+	existing, err := u.identityRepo.GetByProviderSubject(ctx, providerName, identity.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return nil, domain.ErrConflict
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	linked := &domain.UserIdentity{
+		UserID:   userID,
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+	if err := u.identityRepo.Create(ctx, linked); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditIdentityLinked,
+		ActorID:    &userID,
+		TargetType: domain.AuditTargetIdentity,
+		TargetID:   &linked.ID,
+		Metadata:   map[string]string{"provider": providerName},
+	})
+	return linked, nil
+}
+
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string) (*domain.AuthTokens, error) {
+	oldHash := utils.HashToken(refreshToken)
+	session, err := u.sessionRepo.GetByRefreshTokenHash(ctx, oldHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, u.revokeReusedRefreshToken(ctx, oldHash)
+		}
+		return nil, err
+	}
+	// Impersonation sessions are time-boxed and never extended.
+	if !session.IsActive(time.Now()) || session.ImpersonatorID != nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	user, err := u.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	newRefreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(u.cfg.RefreshTokenTTL)
+	if err := u.sessionRepo.RotateRefreshToken(ctx, session.ID, oldHash, utils.HashToken(newRefreshToken), expiresAt); err != nil {
+		return nil, err
+	}
+
+	return u.issueTokens(user, session.ID, newRefreshToken)
+}
+
+// revokeReusedRefreshToken handles a refresh token that is not current. If it was
+// rotated out earlier, someone is replaying a copy, and the session it belonged to is
+// revoked so neither the thief nor the owner can keep refreshing it. The caller is
+// refused either way.
+func (u *authUsecase) revokeReusedRefreshToken(ctx context.Context, hash string) error {
+	session, err := u.sessionRepo.GetByRotatedRefreshTokenHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrUnauthorized
+		}
+		return err
+	}
+	if err := u.sessionRepo.Revoke(ctx, session.ID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditRefreshTokenReused,
+		ActorID:    &session.UserID,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   &session.ID,
+	})
+	return domain.ErrUnauthorized
+}
+
+func (u *authUsecase) Logout(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := u.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditLogout,
+		ActorID:    &userID,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   &sessionID,
+	})
+	return nil
+}
+
+func (u *authUsecase) ValidateSession(ctx context.Context, sessionID uuid.UUID) error {
+	session, err := u.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrUnauthorized
+		}
+		return err
+	}
+	now := time.Now()
+	if !session.IsActive(now) {
+		return domain.ErrUnauthorized
+	}
+
+	// Suspending revokes sessions too, but checking the user closes the gap for
+	// requests already in flight and for sessions created before the suspension.
+	user, err := u.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrUnauthorized
+		}
+		return err
+	}
+	if user.IsSuspended() {
+		return domain.ErrAccountSuspended
+	}
+
+	// Impersonation ends as soon as the admin behind it loses the role or is suspended.
+	if session.ImpersonatorID != nil {
+		admin, err := u.userRepo.GetByID(ctx, *session.ImpersonatorID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrUnauthorized
+			}
+			return err
+		}
+		if admin.Role != domain.RoleAdmin || admin.IsSuspended() {
+			return domain.ErrUnauthorized
+		}
+	}
+
+	// Only write last-seen once per interval so ordinary requests stay read-only.
+	if session.LastSeenAt == nil || now.Sub(*session.LastSeenAt) >= sessionTouchInterval {
+		if err := u.sessionRepo.Touch(ctx, session.ID, now); err != nil {
+			log.Printf("failed to update session last seen: %v", err)
+		}
+	}
+	return nil
+}
+
+func (u *authUsecase) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]domain.Session, error) {
+	sessions, err := u.sessionRepo.ListActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+func (u *authUsecase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := u.sessionRepo.RevokeForUser(ctx, sessionID, userID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditSessionRevoked,
+		ActorID:    &userID,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   &sessionID,
+	})
+	return nil
+}
+
+// ForgotPassword mails a reset link when a local account exists. It never reports whether
+// the email is registered, so callers must respond identically in every case.
+func (u *authUsecase) ForgotPassword(ctx context.Context, email string) error {
+	user, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.Provider != "local" {
+		return nil
+	}
+
+	rawToken, err := u.issueUserToken(ctx, user.ID, domain.TokenPurposePasswordReset, u.cfg.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", u.cfg.AppBaseURL, rawToken)
+	body := fmt.Sprintf("We received a request to reset your password.\n\nOpen the link below within %s to choose a new one:\n%s\n\nIf you did not ask for this, you can ignore this email.", u.cfg.PasswordResetTTL, link)
+	if err := u.mailer.Send(ctx, user.Email, "Reset your password", body); err != nil {
+		log.Printf("failed to send password reset email: %v", err)
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditPasswordResetRequested,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
+
+	return nil
+}
+
+func (u *authUsecase) ResetPassword(ctx context.Context, rawToken, newPassword string) error {
+	token, err := u.tokenRepo.GetByHash(ctx, domain.TokenPurposePasswordReset, utils.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrBadRequest
+		}
+		return err
+	}
+	if !token.IsUsable(time.Now()) {
+		return domain.ErrBadRequest
+	}
+
+	if err := u.tokenRepo.MarkUsed(ctx, token.ID); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepo.UpdatePassword(ctx, token.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+	if err := u.sessionRepo.RevokeAllByUserID(ctx, token.UserID); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditPasswordReset,
+		ActorID:    &token.UserID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &token.UserID,
+	})
+	return nil
+}
+
+func (u *authUsecase) VerifyEmail(ctx context.Context, rawToken string) error {
+	token, err := u.tokenRepo.GetByHash(ctx, domain.TokenPurposeEmailVerification, utils.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrBadRequest
 		}
+		return err
+	}
+	if !token.IsUsable(time.Now()) {
+		return domain.ErrBadRequest
+	}
+
+	if err := u.tokenRepo.MarkUsed(ctx, token.ID); err != nil {
+		return err
+	}
+
+	if err := u.userRepo.MarkEmailVerified(ctx, token.UserID, time.Now()); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditEmailVerified,
+		ActorID:    &token.UserID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &token.UserID,
+	})
+	return nil
+}
+
+// Verification emails are limited to one per minute and five per hour for each user.
+const (
+	verificationResendInterval = time.Minute
+	verificationHourlyLimit    = 5
+)
+
+func (u *authUsecase) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return domain.ErrBadRequest
+	}
+
+	now := time.Now()
+	recent, err := u.tokenRepo.CountCreatedSince(ctx, user.ID, domain.TokenPurposeEmailVerification, now.Add(-verificationResendInterval))
+	if err != nil {
+		return err
+	}
+	hourly, err := u.tokenRepo.CountCreatedSince(ctx, user.ID, domain.TokenPurposeEmailVerification, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if recent > 0 || hourly >= verificationHourlyLimit {
+		return domain.ErrTooManyRequests
+	}
+
+	return u.sendVerificationEmail(ctx, user)
+}
+
+func (u *authUsecase) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	rawToken, err := u.issueUserToken(ctx, user.ID, domain.TokenPurposeEmailVerification, u.cfg.EmailVerifyTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", u.cfg.AppBaseURL, rawToken)
+	body := fmt.Sprintf("Please confirm your email address by opening the link below within %s:\n%s", u.cfg.EmailVerifyTTL, link)
+	return u.mailer.Send(ctx, user.Email, "Verify your email address", body)
+}
+
+// issueUserToken replaces any outstanding token of the same purpose and returns the raw value to mail out.
+func (u *authUsecase) issueUserToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	if err := u.tokenRepo.InvalidateByUserID(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	token := &domain.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := u.tokenRepo.Create(ctx, token); err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
+
+const (
+	sessionTouchInterval = time.Minute
+	maxUserAgentLength   = 512
+)
+
+const (
+	twoFactorChallengeTTL = 5 * time.Minute
+	totpSkew              = 1
+	recoveryCodeCount     = 10
+)
+
+func (u *authUsecase) VerifyTwoFactor(ctx context.Context, challengeToken, code string, client domain.ClientInfo) (*domain.AuthTokens, error) {
+	userID, err := utils.ParseChallengeToken(u.keys, challengeToken)
+	if err != nil {
+		return nil, domain.ErrUnauthorized
+	}
+
+	accountKey := twoFactorThrottleKey(userID)
+	ipKey := "ip:" + client.IP
+	if err := u.checkThrottles(ctx, accountKey, ipKey); err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return nil, domain.ErrUnauthorized
+	}
+	if user.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	if err := u.checkSecondFactor(ctx, user, code); err != nil {
+		if err == domain.ErrUnauthorized {
+			u.recordFailure(ctx, accountKey, ipKey)
+			recordAudit(ctx, u.audit, domain.AuditEvent{
+				Action:     domain.AuditTwoFactorFailed,
+				ActorID:    &user.ID,
+				TargetType: domain.AuditTargetUser,
+				TargetID:   &user.ID,
+			})
+		}
+		return nil, err
+	}
+
+	if err := u.accountThrottle.reset(ctx, accountKey); err != nil {
+		log.Printf("failed to reset login throttle: %v", err)
+	}
+
+	return u.startSession(ctx, user, client)
+}
+
+func (u *authUsecase) EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*domain.TwoFactorEnrollment, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() {
+		return nil, domain.ErrBadRequest
 	}
 
-	return utils.GenerateToken(user.ID, user.Role, u.cfg.JWTSecret)
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.SetTwoFactor(ctx, user.ID, secret, nil); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, u.cfg.TOTPIssuer, user.Email),
+	}, nil
+}
+
+// ConfirmTwoFactor turns on 2FA once the user proves their authenticator works, and returns
+// the recovery codes in plain text. They are only stored hashed, so this is the one chance to show them.
+func (u *authUsecase) ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsTwoFactorEnabled() || user.TOTPSecret == "" {
+		return nil, domain.ErrBadRequest
+	}
+
+	step, ok := totp.Validate(code, user.TOTPSecret, time.Now(), totpSkew)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, recoveryCode)
+		hashes = append(hashes, utils.HashToken(normalizeRecoveryCode(recoveryCode)))
+	}
+	if err := u.recoveryRepo.Replace(ctx, user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	enabledAt := time.Now()
+	if err := u.userRepo.SetTwoFactor(ctx, user.ID, user.TOTPSecret, &enabledAt); err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.AdvanceTOTPStep(ctx, user.ID, step); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditTwoFactorEnabled,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
+	return codes, nil
+}
+
+// DisableTwoFactor shares the attempt limit of VerifyTwoFactor, so a stolen access
+// token cannot be used to guess codes until 2FA turns off.
+func (u *authUsecase) DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	accountKey := twoFactorThrottleKey(userID)
+	if err := u.accountThrottle.check(ctx, accountKey); err != nil {
+		return err
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsTwoFactorEnabled() {
+		return domain.ErrBadRequest
+	}
+
+	if err := u.checkSecondFactor(ctx, user, code); err != nil {
+		if err == domain.ErrUnauthorized {
+			if err := u.accountThrottle.fail(ctx, accountKey); err != nil {
+				log.Printf("failed to record login failure: %v", err)
+			}
+			recordAudit(ctx, u.audit, domain.AuditEvent{
+				Action:     domain.AuditTwoFactorFailed,
+				ActorID:    &user.ID,
+				TargetType: domain.AuditTargetUser,
+				TargetID:   &user.ID,
+			})
+		}
+		return err
+	}
+	if err := u.accountThrottle.reset(ctx, accountKey); err != nil {
+		log.Printf("failed to reset login throttle: %v", err)
+	}
+
+	if err := u.recoveryRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := u.userRepo.SetTwoFactor(ctx, user.ID, "", nil); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditTwoFactorDisabled,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   &user.ID,
+	})
+	return nil
+}
+
+func twoFactorThrottleKey(userID uuid.UUID) string {
+	return "2fa:" + userID.String()
+}
+
+// checkSecondFactor accepts either a current TOTP code or an unused recovery code.
+func (u *authUsecase) checkSecondFactor(ctx context.Context, user *domain.User, code string) error {
+	if step, ok := totp.Validate(code, user.TOTPSecret, time.Now(), totpSkew); ok {
+		return u.userRepo.AdvanceTOTPStep(ctx, user.ID, step)
+	}
+
+	return u.recoveryRepo.Consume(ctx, user.ID, utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+func (u *authUsecase) checkThrottles(ctx context.Context, accountKey, ipKey string) error {
+	if err := u.accountThrottle.check(ctx, accountKey); err != nil {
+		return err
+	}
+	return u.ipThrottle.check(ctx, ipKey)
+}
+
+// recordFailure counts a failed attempt against both keys. Throttling is best effort,
+// so storage errors are logged rather than surfaced to the client.
+func (u *authUsecase) recordFailure(ctx context.Context, accountKey, ipKey string) {
+	if err := u.accountThrottle.fail(ctx, accountKey); err != nil {
+		log.Printf("failed to record login failure: %v", err)
+	}
+	if err := u.ipThrottle.fail(ctx, ipKey); err != nil {
+		log.Printf("failed to record login failure: %v", err)
+	}
+}
+
+func (u *authUsecase) completeLogin(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.LoginResult, error) {
+	if user.IsSuspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	if user.IsTwoFactorEnabled() {
+		challenge, err := utils.GenerateChallengeToken(u.keys, user.ID, twoFactorChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{ChallengeToken: challenge}, nil
+	}
+
+	tokens, err := u.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Tokens: tokens}, nil
+}
+
+func (u *authUsecase) startSession(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.AuthTokens, error) {
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        userAgent,
+		IP:               client.IP,
+		LastSeenAt:       &now,
+		ExpiresAt:        now.Add(u.cfg.RefreshTokenTTL),
+	}
+	if err := u.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditLogin,
+		ActorID:    &user.ID,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   &session.ID,
+		Metadata:   map[string]string{"provider": user.Provider},
+	})
+
+	return u.issueTokens(user, session.ID, refreshToken)
+}
+
+func (u *authUsecase) issueTokens(user *domain.User, sessionID uuid.UUID, refreshToken string) (*domain.AuthTokens, error) {
+	accessToken, expiresAt, err := utils.GenerateToken(u.keys, user.ID, sessionID, user.Role, u.cfg.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// generateRecoveryCode returns a code such as "K7QM2-XW4PA" that is easy to type from paper.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	encoded := base32.StdEncoding.EncodeToString(b)[:10]
+	return encoded[:5] + "-" + encoded[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
 }
@@ -0,0 +1,750 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"be-job-portal/internal/config"
+	"be-job-portal/internal/domain"
+	"be-job-portal/internal/repository"
+	"be-job-portal/pkg/oidc"
+	"be-job-portal/pkg/totp"
+	"be-job-portal/pkg/utils"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestAuthUsecase(t *testing.T, users *fakeUserRepo, sessions *fakeSessionRepo, audit *fakeAuditLogger) *authUsecase {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := utils.NewKeySet(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{
+		AccessTokenTTL:     time.Minute,
+		RefreshTokenTTL:    time.Hour,
+		LoginMaxFailures:   3,
+		LoginMaxFailuresIP: 100,
+		LoginLockout:       time.Minute,
+		LoginMaxLockout:    time.Hour,
+		LoginFailureWindow: 15 * time.Minute,
+		PasswordResetTTL:   time.Hour,
+		EmailVerifyTTL:     24 * time.Hour,
+		AppBaseURL:         "https://jobs.example.com",
+	}
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	return NewAuthUsecase(users, sessions, nil, fakeRecoveryCodeRepo{}, attempts, nil, nil, nil, nil, keys, audit, cfg).(*authUsecase)
+}
+
+func TestRefreshRotatesAndRevokesOnReuse(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleSeeker}
+	sessions := newFakeSessionRepo()
+	audit := &fakeAuditLogger{}
+	u := newTestAuthUsecase(t, newFakeUserRepo(user), sessions, audit)
+
+	session := &domain.Session{UserID: user.ID, RefreshTokenHash: utils.HashToken("first"), ExpiresAt: time.Now().Add(time.Hour)}
+	if err := sessions.Create(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := u.Refresh(ctx, "first")
+	if err != nil {
+		t.Fatalf("first refresh: %v", err)
+	}
+	if tokens.RefreshToken == "" || tokens.RefreshToken == "first" {
+		t.Fatalf("refresh token was not rotated: %q", tokens.RefreshToken)
+	}
+	claims, err := utils.ParseToken(u.keys, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("access token: %v", err)
+	}
+	if claims.SessionID != session.ID || claims.UserID != user.ID {
+		t.Fatalf("access token claims = %+v", claims)
+	}
+
+	// Replaying the rotated token is treated as theft.
+	if _, err := u.Refresh(ctx, "first"); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Fatalf("reused token: err = %v, want ErrUnauthorized", err)
+	}
+	if sessions.sessions[session.ID].RevokedAt == nil {
+		t.Fatal("reusing a rotated token did not revoke the session")
+	}
+	if got := audit.actions(); len(got) != 1 || got[0] != domain.AuditRefreshTokenReused {
+		t.Fatalf("audit actions = %v, want [%s]", got, domain.AuditRefreshTokenReused)
+	}
+
+	// The legitimate holder's newer token dies with the session.
+	if _, err := u.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Fatalf("token of revoked session: err = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRefreshUnknownTokenRevokesNothing(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleSeeker}
+	sessions := newFakeSessionRepo()
+	audit := &fakeAuditLogger{}
+	u := newTestAuthUsecase(t, newFakeUserRepo(user), sessions, audit)
+
+	session := &domain.Session{UserID: user.ID, RefreshTokenHash: utils.HashToken("current"), ExpiresAt: time.Now().Add(time.Hour)}
+	if err := sessions.Create(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := u.Refresh(ctx, "never-issued"); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Fatalf("err = %v, want ErrUnauthorized", err)
+	}
+	if sessions.sessions[session.ID].RevokedAt != nil {
+		t.Fatal("an unknown token revoked an unrelated session")
+	}
+	if len(audit.actions()) != 0 {
+		t.Fatalf("audit actions = %v, want none", audit.actions())
+	}
+}
+
+func TestRefreshRejectsExpiredAndImpersonatedSessions(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleSeeker}
+	adminID := uuid.New()
+
+	tests := []struct {
+		name    string
+		session domain.Session
+	}{
+		{"expired", domain.Session{ExpiresAt: time.Now().Add(-time.Minute)}},
+		{"impersonated", domain.Session{ExpiresAt: time.Now().Add(time.Hour), ImpersonatorID: &adminID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := newFakeSessionRepo()
+			u := newTestAuthUsecase(t, newFakeUserRepo(user), sessions, &fakeAuditLogger{})
+			session := tt.session
+			session.UserID = user.ID
+			session.RefreshTokenHash = utils.HashToken("token")
+			if err := sessions.Create(ctx, &session); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := u.Refresh(ctx, "token"); !errors.Is(err, domain.ErrUnauthorized) {
+				t.Fatalf("err = %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+}
+
+func TestDisableTwoFactorIsThrottled(t *testing.T) {
+	ctx := context.Background()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabledAt := time.Now()
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleSeeker, TOTPSecret: secret, TwoFactorEnabledAt: &enabledAt}
+	users := newFakeUserRepo(user)
+	u := newTestAuthUsecase(t, users, newFakeSessionRepo(), &fakeAuditLogger{})
+
+	for i := 0; i < u.cfg.LoginMaxFailures; i++ {
+		if err := u.DisableTwoFactor(ctx, user.ID, "000000"); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Fatalf("attempt %d: err = %v, want ErrUnauthorized", i+1, err)
+		}
+	}
+
+	// Once locked, even the right code is refused and 2FA stays on.
+	code, err := totp.CodeAt(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = u.DisableTwoFactor(ctx, user.ID, code)
+	var retryErr *domain.RetryAfterError
+	if !errors.As(err, &retryErr) || retryErr.RetryAfter <= 0 {
+		t.Fatalf("err = %v, want a RetryAfterError", err)
+	}
+	if users.users[user.ID].TwoFactorEnabledAt == nil {
+		t.Fatal("2FA was disabled while the account was locked")
+	}
+
+	// VerifyTwoFactor draws on the same budget.
+	challenge, err := utils.GenerateChallengeToken(u.keys, user.ID, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.VerifyTwoFactor(ctx, challenge, code, domain.ClientInfo{IP: "203.0.113.1"}); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("VerifyTwoFactor err = %v, want ErrTooManyRequests", err)
+	}
+}
+
+func TestDisableTwoFactorWithValidCode(t *testing.T) {
+	ctx := context.Background()
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabledAt := time.Now()
+	user := &domain.User{ID: uuid.New(), Role: domain.RoleSeeker, TOTPSecret: secret, TwoFactorEnabledAt: &enabledAt}
+	users := newFakeUserRepo(user)
+	audit := &fakeAuditLogger{}
+	u := newTestAuthUsecase(t, users, newFakeSessionRepo(), audit)
+
+	code, err := totp.CodeAt(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.DisableTwoFactor(ctx, user.ID, code); err != nil {
+		t.Fatalf("DisableTwoFactor: %v", err)
+	}
+	if users.users[user.ID].TwoFactorEnabledAt != nil {
+		t.Fatal("2FA is still enabled")
+	}
+	if got := audit.actions(); len(got) != 1 || got[0] != domain.AuditTwoFactorDisabled {
+		t.Fatalf("audit actions = %v", got)
+	}
+}
+
+func TestResolveIdentityAdoptsLegacyAccountOnlyWithVerifiedEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		verified bool
+		wantErr  error
+	}{
+		{name: "verified email from the signup provider", provider: "google", verified: true},
+		{name: "unverified email", provider: "google", verified: false, wantErr: domain.ErrConflict},
+		{name: "account from another provider", provider: "local", verified: true, wantErr: domain.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := &domain.User{ID: uuid.New(), Email: "ada@example.com", Role: domain.RoleSeeker, Provider: tt.provider}
+			identities := &fakeIdentityRepo{}
+			u := newTestAuthUsecase(t, newFakeUserRepo(user), newFakeSessionRepo(), &fakeAuditLogger{})
+			u.identityRepo = identities
+
+			got, err := u.resolveIdentity(ctx, "google", &oidc.Identity{Subject: "sub-1", Email: user.Email, EmailVerified: tt.verified})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if len(identities.identities) != 0 {
+					t.Fatal("identity was linked despite the error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != user.ID || len(identities.identities) != 1 || identities.identities[0].UserID != user.ID {
+				t.Fatalf("legacy account was not adopted: user %v, identities %+v", got.ID, identities.identities)
+			}
+		})
+	}
+}
+
+// newPasswordUser returns a local user whose password is password, hashed cheaply.
+func newPasswordUser(t *testing.T, email, password string) *domain.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &domain.User{ID: uuid.New(), Email: email, Password: string(hash), Role: domain.RoleSeeker, Provider: "local"}
+}
+
+func TestLoginLocksAccountAfterMaxFailures(t *testing.T) {
+	ctx := context.Background()
+	user := newPasswordUser(t, "ada@example.com", "correct horse")
+	sessions := newFakeSessionRepo()
+	u := newTestAuthUsecase(t, newFakeUserRepo(user), sessions, &fakeAuditLogger{})
+	client := domain.ClientInfo{IP: "203.0.113.7"}
+
+	for i := 0; i < u.cfg.LoginMaxFailures; i++ {
+		if _, err := u.Login(ctx, user.Email, "wrong", client); err == nil || errors.Is(err, domain.ErrTooManyRequests) {
+			t.Fatalf("attempt %d: err = %v, want invalid credentials", i+1, err)
+		}
+	}
+
+	// Locked out: even the right password is refused, and no session starts.
+	_, err := u.Login(ctx, user.Email, "correct horse", client)
+	var retryErr *domain.RetryAfterError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("err = %v, want a RetryAfterError", err)
+	}
+	if retryErr.RetryAfter <= 0 || retryErr.RetryAfter > u.cfg.LoginLockout {
+		t.Fatalf("RetryAfter = %v, want up to %v", retryErr.RetryAfter, u.cfg.LoginLockout)
+	}
+	if len(sessions.sessions) != 0 {
+		t.Fatal("a locked account was signed in")
+	}
+
+	// The lock follows the account, whatever the case of the address or the client.
+	if _, err := u.Login(ctx, "ADA@example.com", "wrong", domain.ClientInfo{IP: "198.51.100.1"}); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("other client: err = %v, want ErrTooManyRequests", err)
+	}
+	// Other accounts are unaffected.
+	other := newPasswordUser(t, "grace@example.com", "hunter2")
+	u.userRepo.(*fakeUserRepo).users[other.ID] = other
+	if _, err := u.Login(ctx, other.Email, "hunter2", client); err != nil {
+		t.Fatalf("other account: %v", err)
+	}
+}
+
+func TestLoginSuccessResetsFailures(t *testing.T) {
+	ctx := context.Background()
+	user := newPasswordUser(t, "ada@example.com", "correct horse")
+	u := newTestAuthUsecase(t, newFakeUserRepo(user), newFakeSessionRepo(), &fakeAuditLogger{})
+	client := domain.ClientInfo{IP: "203.0.113.7"}
+
+	fail := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			if _, err := u.Login(ctx, user.Email, "wrong", client); err == nil || errors.Is(err, domain.ErrTooManyRequests) {
+				t.Fatalf("failure %d: err = %v", i+1, err)
+			}
+		}
+	}
+
+	fail(u.cfg.LoginMaxFailures - 1)
+	result, err := u.Login(ctx, user.Email, "correct horse", client)
+	if err != nil || result.Tokens == nil {
+		t.Fatalf("login: %+v, %v", result, err)
+	}
+
+	// Without the reset, the next failure would reach the limit.
+	fail(u.cfg.LoginMaxFailures - 1)
+	if _, err := u.Login(ctx, user.Email, "correct horse", client); err != nil {
+		t.Fatalf("login after reset: %v", err)
+	}
+}
+
+func TestLoginFailuresOutsideTheWindowStartOver(t *testing.T) {
+	ctx := context.Background()
+	user := newPasswordUser(t, "ada@example.com", "correct horse")
+	u := newTestAuthUsecase(t, newFakeUserRepo(user), newFakeSessionRepo(), &fakeAuditLogger{})
+	client := domain.ClientInfo{IP: "203.0.113.7"}
+	attempts := u.accountThrottle.store
+	key := "email:" + user.Email
+
+	// A lockout that ran out longer ago than the window.
+	old := time.Now().Add(-2 * u.cfg.LoginFailureWindow)
+	for i := 0; i < u.cfg.LoginMaxFailures; i++ {
+		if _, err := attempts.RecordFailure(ctx, key, old, u.cfg.LoginFailureWindow); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := attempts.Lock(ctx, key, old.Add(u.cfg.LoginLockout)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Login(ctx, user.Email, "wrong", client); errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatal("an expired lockout still locks the account")
+	}
+
+	// The failure above started a fresh count instead of adding to the old one.
+	attempt, err := attempts.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempt == nil || attempt.Failures != 1 || attempt.LockedUntil != nil {
+		t.Fatalf("attempt = %+v, want a fresh count of 1", attempt)
+	}
+}
+
+func TestLoginFailureRightAfterLockoutDoublesIt(t *testing.T) {
+	ctx := context.Background()
+	user := newPasswordUser(t, "ada@example.com", "correct horse")
+	u := newTestAuthUsecase(t, newFakeUserRepo(user), newFakeSessionRepo(), &fakeAuditLogger{})
+	client := domain.ClientInfo{IP: "203.0.113.7"}
+	attempts := u.accountThrottle.store
+	key := "email:" + user.Email
+
+	// The first lockout has just run out, well within the window.
+	recent := time.Now().Add(-u.cfg.LoginLockout - time.Second)
+	for i := 0; i < u.cfg.LoginMaxFailures; i++ {
+		if _, err := attempts.RecordFailure(ctx, key, recent, u.cfg.LoginFailureWindow); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := attempts.Lock(ctx, key, recent.Add(u.cfg.LoginLockout)); err != nil {
+		t.Fatal(err)
+	}
+
+	// One more try is allowed, but failing it locks the account for twice as long.
+	if _, err := u.Login(ctx, user.Email, "wrong", client); err == nil || errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("err = %v, want invalid credentials", err)
+	}
+	_, err := u.Login(ctx, user.Email, "correct horse", client)
+	var retryErr *domain.RetryAfterError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("err = %v, want a RetryAfterError", err)
+	}
+	if retryErr.RetryAfter <= u.cfg.LoginLockout || retryErr.RetryAfter > 2*u.cfg.LoginLockout {
+		t.Fatalf("RetryAfter = %v, want just under %v", retryErr.RetryAfter, 2*u.cfg.LoginLockout)
+	}
+}
+
+func TestLoginLocksIPAcrossAccounts(t *testing.T) {
+	ctx := context.Background()
+	u := newTestAuthUsecase(t, newFakeUserRepo(), newFakeSessionRepo(), &fakeAuditLogger{})
+	u.ipThrottle.maxFailures = 4
+	client := domain.ClientInfo{IP: "203.0.113.7"}
+
+	// Spraying different addresses keeps each account under its limit.
+	for i := 0; i < u.ipThrottle.maxFailures; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if _, err := u.Login(ctx, email, "guess", client); err == nil || errors.Is(err, domain.ErrTooManyRequests) {
+			t.Fatalf("attempt %d: err = %v", i+1, err)
+		}
+	}
+	if _, err := u.Login(ctx, "fresh@example.com", "guess", client); !errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatalf("same IP: err = %v, want ErrTooManyRequests", err)
+	}
+	if _, err := u.Login(ctx, "fresh@example.com", "guess", domain.ClientInfo{IP: "198.51.100.1"}); errors.Is(err, domain.ErrTooManyRequests) {
+		t.Fatal("another IP was locked too")
+	}
+}
+
+// mailedToken returns the raw token from the link in a mail sent by the usecase.
+func mailedToken(t *testing.T, mail sentMail) string {
+	t.Helper()
+	_, rest, ok := strings.Cut(mail.body, "?token=")
+	if !ok {
+		t.Fatalf("no token link in %q", mail.body)
+	}
+	return strings.Fields(rest)[0]
+}
+
+func TestForgotPasswordDoesNotRevealAccounts(t *testing.T) {
+	ctx := context.Background()
+	local := newPasswordUser(t, "ada@example.com", "correct horse")
+	google := &domain.User{ID: uuid.New(), Email: "grace@example.com", Role: domain.RoleSeeker, Provider: "google"}
+	tokens := &fakeUserTokenRepo{}
+	mailer := &fakeMailer{failFor: map[string]bool{"linus@example.com": true}}
+	broken := newPasswordUser(t, "linus@example.com", "hunter2")
+	u := newTestAuthUsecase(t, newFakeUserRepo(local, google, broken), newFakeSessionRepo(), &fakeAuditLogger{})
+	u.tokenRepo = tokens
+	u.mailer = mailer
+
+	// Every address gets the same answer, even when the mail cannot be sent.
+	for _, email := range []string{"nobody@example.com", google.Email, broken.Email, local.Email} {
+		if err := u.ForgotPassword(ctx, email); err != nil {
+			t.Fatalf("%s: %v", email, err)
+		}
+	}
+
+	if len(mailer.sent) != 1 || mailer.sent[0].to != local.Email {
+		t.Fatalf("sent %+v, want one mail to %s", mailer.sent, local.Email)
+	}
+	raw := mailedToken(t, mailer.sent[0])
+	if !strings.HasPrefix(mailer.sent[0].body, "We received") || !strings.Contains(mailer.sent[0].body, "https://jobs.example.com/reset-password?token=") {
+		t.Fatalf("unexpected body %q", mailer.sent[0].body)
+	}
+	var issued []*domain.UserToken
+	for _, token := range tokens.tokens {
+		if token.UserID == local.ID {
+			issued = append(issued, token)
+		}
+		if token.UserID == google.ID {
+			t.Fatal("a token was issued for a Google account")
+		}
+	}
+	if len(issued) != 1 {
+		t.Fatalf("issued %d tokens for the local account, want 1", len(issued))
+	}
+	token := issued[0]
+	if token.Purpose != domain.TokenPurposePasswordReset || token.TokenHash != utils.HashToken(raw) || token.TokenHash == raw {
+		t.Fatalf("stored token = %+v, want the hash of the mailed one", token)
+	}
+	if ttl := time.Until(token.ExpiresAt); ttl <= 0 || ttl > u.cfg.PasswordResetTTL {
+		t.Fatalf("token expires in %v, want within %v", ttl, u.cfg.PasswordResetTTL)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	user := newPasswordUser(t, "ada@example.com", "correct horse")
+	users := newFakeUserRepo(user)
+	sessions := newFakeSessionRepo()
+	audit := &fakeAuditLogger{}
+	tokens := &fakeUserTokenRepo{}
+	mailer := &fakeMailer{}
+	u := newTestAuthUsecase(t, users, sessions, audit)
+	u.tokenRepo = tokens
+	u.mailer = mailer
+
+	otherUser := uuid.New()
+	for _, session := range []*domain.Session{{UserID: user.ID}, {UserID: user.ID}, {UserID: otherUser}} {
+		sessions.Create(ctx, session)
+	}
+
+	if err := u.ForgotPassword(ctx, user.Email); err != nil {
+		t.Fatal(err)
+	}
+	raw := mailedToken(t, mailer.sent[0])
+
+	if err := u.ResetPassword(ctx, raw, "new password"); err != nil {
+		t.Fatal(err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(users.users[user.ID].Password), []byte("new password")) != nil {
+		t.Fatal("password was not changed")
+	}
+	for _, session := range sessions.sessions {
+		if revoked := session.RevokedAt != nil; revoked != (session.UserID == user.ID) {
+			t.Fatalf("session of %v revoked = %v", session.UserID, revoked)
+		}
+	}
+	if got := audit.actions(); got[len(got)-1] != domain.AuditPasswordReset {
+		t.Fatalf("audit = %v, want it to end with %s", got, domain.AuditPasswordReset)
+	}
+
+	// The token works once.
+	if err := u.ResetPassword(ctx, raw, "another password"); !errors.Is(err, domain.ErrBadRequest) {
+		t.Fatalf("reuse: err = %v, want ErrBadRequest", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(users.users[user.ID].Password), []byte("new password")) != nil {
+		t.Fatal("a used token changed the password")
+	}
+}
+
+func TestResetPasswordRejectsUnusableTokens(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// setup returns the raw token to try.
+		setup func(u *authUsecase, user *domain.User, tokens *fakeUserTokenRepo, mailer *fakeMailer) string
+	}{
+		{
+			name: "unknown token",
+			setup: func(u *authUsecase, user *domain.User, tokens *fakeUserTokenRepo, mailer *fakeMailer) string {
+				return "not-a-token"
+			},
+		},
+		{
+			name: "expired",
+			setup: func(u *authUsecase, user *domain.User, tokens *fakeUserTokenRepo, mailer *fakeMailer) string {
+				tokens.Create(ctx, &domain.UserToken{
+					UserID:    user.ID,
+					Purpose:   domain.TokenPurposePasswordReset,
+					TokenHash: utils.HashToken("expired"),
+					ExpiresAt: time.Now().Add(-time.Second),
+				})
+				return "expired"
+			},
+		},
+		{
+			name: "replaced by a newer request",
+			setup: func(u *authUsecase, user *domain.User, tokens *fakeUserTokenRepo, mailer *fakeMailer) string {
+				u.ForgotPassword(ctx, user.Email)
+				u.ForgotPassword(ctx, user.Email)
+				return mailedToken(t, mailer.sent[0])
+			},
+		},
+		{
+			name: "email verification token",
+			setup: func(u *authUsecase, user *domain.User, tokens *fakeUserTokenRepo, mailer *fakeMailer) string {
+				u.sendVerificationEmail(ctx, user)
+				return mailedToken(t, mailer.sent[0])
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newPasswordUser(t, "ada@example.com", "correct horse")
+			users := newFakeUserRepo(user)
+			sessions := newFakeSessionRepo()
+			tokens := &fakeUserTokenRepo{}
+			mailer := &fakeMailer{}
+			u := newTestAuthUsecase(t, users, sessions, &fakeAuditLogger{})
+			u.tokenRepo = tokens
+			u.mailer = mailer
+			sessions.Create(ctx, &domain.Session{UserID: user.ID})
+			passwordHash := user.Password
+
+			raw := tt.setup(u, user, tokens, mailer)
+			if err := u.ResetPassword(ctx, raw, "new password"); !errors.Is(err, domain.ErrBadRequest) {
+				t.Fatalf("err = %v, want ErrBadRequest", err)
+			}
+			if users.users[user.ID].Password != passwordHash {
+				t.Fatal("a rejected reset changed the password")
+			}
+			for _, session := range sessions.sessions {
+				if session.RevokedAt != nil {
+					t.Fatal("a rejected reset revoked sessions")
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterAndVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	users := newFakeUserRepo()
+	tokens := &fakeUserTokenRepo{}
+	mailer := &fakeMailer{}
+	audit := &fakeAuditLogger{}
+	u := newTestAuthUsecase(t, users, newFakeSessionRepo(), audit)
+	u.tokenRepo = tokens
+	u.mailer = mailer
+
+	if err := u.Register(ctx, "ada@example.com", "correct horse", domain.RoleRecruiter); err != nil {
+		t.Fatal(err)
+	}
+	user, err := users.GetByEmail(ctx, "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.IsEmailVerified() {
+		t.Fatal("a new local account starts verified")
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].to != user.Email || !strings.Contains(mailer.sent[0].body, "https://jobs.example.com/verify-email?token=") {
+		t.Fatalf("sent %+v, want one verification mail", mailer.sent)
+	}
+	raw := mailedToken(t, mailer.sent[0])
+
+	// A verification link cannot reset the password.
+	if err := u.ResetPassword(ctx, raw, "new password"); !errors.Is(err, domain.ErrBadRequest) {
+		t.Fatalf("reset with a verification token: err = %v, want ErrBadRequest", err)
+	}
+
+	if err := u.VerifyEmail(ctx, raw); err != nil {
+		t.Fatal(err)
+	}
+	if !users.users[user.ID].IsEmailVerified() {
+		t.Fatal("email was not marked verified")
+	}
+	if got := audit.actions(); got[len(got)-1] != domain.AuditEmailVerified {
+		t.Fatalf("audit = %v, want it to end with %s", got, domain.AuditEmailVerified)
+	}
+	if err := u.VerifyEmail(ctx, raw); !errors.Is(err, domain.ErrBadRequest) {
+		t.Fatalf("reuse: err = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestVerifyEmailRejectsUnusableTokens(t *testing.T) {
+	ctx := context.Background()
+	user := newPasswordUser(t, "ada@example.com", "correct horse")
+	users := newFakeUserRepo(user)
+	tokens := &fakeUserTokenRepo{}
+	mailer := &fakeMailer{}
+	u := newTestAuthUsecase(t, users, newFakeSessionRepo(), &fakeAuditLogger{})
+	u.tokenRepo = tokens
+	u.mailer = mailer
+
+	tokens.Create(ctx, &domain.UserToken{
+		UserID:    user.ID,
+		Purpose:   domain.TokenPurposeEmailVerification,
+		TokenHash: utils.HashToken("expired"),
+		ExpiresAt: time.Now().Add(-time.Second),
+	})
+	if err := u.ForgotPassword(ctx, user.Email); err != nil {
+		t.Fatal(err)
+	}
+	resetToken := mailedToken(t, mailer.sent[0])
+
+	for _, raw := range []string{"not-a-token", "expired", resetToken} {
+		if err := u.VerifyEmail(ctx, raw); !errors.Is(err, domain.ErrBadRequest) {
+			t.Fatalf("VerifyEmail(%q): err = %v, want ErrBadRequest", raw, err)
+		}
+	}
+	if users.users[user.ID].IsEmailVerified() {
+		t.Fatal("an unusable token verified the email")
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	ctx := context.Background()
+	// issued seeds a verification token created ago before the call.
+	issued := func(tokens *fakeUserTokenRepo, userID uuid.UUID, ago time.Duration) {
+		tokens.Create(ctx, &domain.UserToken{
+			UserID:    userID,
+			Purpose:   domain.TokenPurposeEmailVerification,
+			TokenHash: utils.HashToken(uuid.NewString()),
+			CreatedAt: time.Now().Add(-ago),
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+	}
+
+	tests := []struct {
+		name     string
+		verified bool
+		sent     []time.Duration
+		wantErr  error
+	}{
+		{name: "first mail", sent: nil},
+		{name: "a minute after the last", sent: []time.Duration{2 * time.Minute}},
+		{name: "within a minute of the last", sent: []time.Duration{30 * time.Second}, wantErr: domain.ErrTooManyRequests},
+		{name: "fourth in the hour", sent: []time.Duration{50 * time.Minute, 40 * time.Minute, 30 * time.Minute}},
+		{name: "sixth in the hour", sent: []time.Duration{50 * time.Minute, 40 * time.Minute, 30 * time.Minute, 20 * time.Minute, 10 * time.Minute}, wantErr: domain.ErrTooManyRequests},
+		{name: "older mails do not count", sent: []time.Duration{5 * time.Hour, 4 * time.Hour, 3 * time.Hour, 2 * time.Hour, 61 * time.Minute}},
+		{name: "already verified", verified: true, wantErr: domain.ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newPasswordUser(t, "ada@example.com", "correct horse")
+			if tt.verified {
+				verifiedAt := time.Now()
+				user.EmailVerifiedAt = &verifiedAt
+			}
+			tokens := &fakeUserTokenRepo{}
+			mailer := &fakeMailer{}
+			u := newTestAuthUsecase(t, newFakeUserRepo(user), newFakeSessionRepo(), &fakeAuditLogger{})
+			u.tokenRepo = tokens
+			u.mailer = mailer
+			for _, ago := range tt.sent {
+				issued(tokens, user.ID, ago)
+			}
+
+			err := u.ResendVerification(ctx, user.ID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if len(mailer.sent) != 0 || len(tokens.tokens) != len(tt.sent) {
+					t.Fatalf("a refused resend sent %d mails and issued %d tokens", len(mailer.sent), len(tokens.tokens)-len(tt.sent))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(mailer.sent) != 1 {
+				t.Fatalf("sent %d mails, want 1", len(mailer.sent))
+			}
+			// Only the newest link stays usable.
+			for _, token := range tokens.tokens {
+				newest := token.TokenHash == utils.HashToken(mailedToken(t, mailer.sent[0]))
+				if usable := token.IsUsable(time.Now()); usable != newest {
+					t.Fatalf("token created %v usable = %v", token.CreatedAt, usable)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveIdentityVerifiesNewAccountsByProvider(t *testing.T) {
+	for _, verified := range []bool{true, false} {
+		t.Run(fmt.Sprint("verified=", verified), func(t *testing.T) {
+			ctx := context.Background()
+			users := newFakeUserRepo()
+			u := newTestAuthUsecase(t, users, newFakeSessionRepo(), &fakeAuditLogger{})
+			u.identityRepo = &fakeIdentityRepo{}
+
+			got, err := u.resolveIdentity(ctx, "google", &oidc.Identity{Subject: "sub-1", Email: "ada@example.com", EmailVerified: verified})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored := users.users[got.ID]; stored.IsEmailVerified() != verified || stored.Provider != "google" {
+				t.Fatalf("stored user = %+v, want verified = %v", stored, verified)
+			}
+		})
+	}
+}
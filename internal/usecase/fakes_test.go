@@ -0,0 +1,554 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The fakes below keep state in memory and implement only what the tests call; the
+// embedded interface panics on anything else.
+
+type fakeUserRepo struct {
+	domain.UserRepository
+	users map[uuid.UUID]*domain.User
+}
+
+func newFakeUserRepo(users ...*domain.User) *fakeUserRepo {
+	r := &fakeUserRepo{users: make(map[uuid.UUID]*domain.User)}
+	for _, user := range users {
+		r.users[user.ID] = user
+	}
+	return r
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error {
+	user.ID = uuid.New()
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) MarkEmailVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	r.users[id].EmailVerifiedAt = &verifiedAt
+	return nil
+}
+
+func (r *fakeUserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	r.users[id].Password = hashedPassword
+	return nil
+}
+
+func (r *fakeUserRepo) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) error {
+	user := r.users[id]
+	if user.TOTPLastStep >= step {
+		return domain.ErrUnauthorized
+	}
+	user.TOTPLastStep = step
+	return nil
+}
+
+func (r *fakeUserRepo) SetTwoFactor(ctx context.Context, id uuid.UUID, secret string, enabledAt *time.Time) error {
+	user := r.users[id]
+	user.TOTPSecret = secret
+	user.TwoFactorEnabledAt = enabledAt
+	return nil
+}
+
+type fakeIdentityRepo struct {
+	domain.UserIdentityRepository
+	identities []domain.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	identity.ID = uuid.New()
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := identity
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeIdentityRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+// fakeRecoveryCodeRepo holds no codes, so every recovery code is wrong.
+type fakeRecoveryCodeRepo struct {
+	domain.RecoveryCodeRepository
+}
+
+func (fakeRecoveryCodeRepo) Consume(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	return domain.ErrUnauthorized
+}
+
+func (fakeRecoveryCodeRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+type fakeSessionRepo struct {
+	domain.SessionRepository
+	sessions map[uuid.UUID]*domain.Session
+	rotated  map[string]uuid.UUID
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{sessions: make(map[uuid.UUID]*domain.Session), rotated: make(map[string]uuid.UUID)}
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session *domain.Session) error {
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *fakeSessionRepo) GetByRefreshTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	for _, session := range r.sessions {
+		if session.RefreshTokenHash == hash {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSessionRepo) GetByRotatedRefreshTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	id, ok := r.rotated[hash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *r.sessions[id]
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) RotateRefreshToken(ctx context.Context, id uuid.UUID, oldHash, newHash string, expiresAt time.Time) error {
+	session, ok := r.sessions[id]
+	if !ok || session.RefreshTokenHash != oldHash || session.RevokedAt != nil {
+		return domain.ErrUnauthorized
+	}
+	session.RefreshTokenHash = newHash
+	session.ExpiresAt = expiresAt
+	r.rotated[oldHash] = id
+	return nil
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	if session, ok := r.sessions[id]; ok && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeAllByUserID(ctx context.Context, userID uuid.UUID) error {
+	for _, session := range r.sessions {
+		if session.UserID == userID {
+			r.Revoke(ctx, session.ID)
+		}
+	}
+	return nil
+}
+
+// fakeUserTokenRepo keeps every token it was given, used or not.
+type fakeUserTokenRepo struct {
+	domain.UserTokenRepository
+	tokens []*domain.UserToken
+}
+
+func (r *fakeUserTokenRepo) Create(ctx context.Context, token *domain.UserToken) error {
+	token.ID = uuid.New()
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	copied := *token
+	r.tokens = append(r.tokens, &copied)
+	return nil
+}
+
+func (r *fakeUserTokenRepo) GetByHash(ctx context.Context, purpose, hash string) (*domain.UserToken, error) {
+	for _, token := range r.tokens {
+		if token.Purpose == purpose && token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserTokenRepo) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	for _, token := range r.tokens {
+		if token.ID == id && token.UsedAt == nil {
+			now := time.Now()
+			token.UsedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrBadRequest
+}
+
+func (r *fakeUserTokenRepo) InvalidateByUserID(ctx context.Context, userID uuid.UUID, purpose string) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserTokenRepo) CountCreatedSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int64, error) {
+	var count int64
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && !token.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type fakeAuditLogger struct {
+	mu     sync.Mutex
+	events []domain.AuditEvent
+}
+
+func (l *fakeAuditLogger) Record(ctx context.Context, event domain.AuditEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+func (l *fakeAuditLogger) actions() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	actions := make([]string, len(l.events))
+	for i, event := range l.events {
+		actions[i] = event.Action
+	}
+	return actions
+}
+
+// fakeOrgRepo records the membership changes made through it.
+type fakeOrgRepo struct {
+	domain.OrganizationRepository
+	members     map[uuid.UUID]*domain.OrganizationMember
+	invitations map[string]*domain.OrganizationInvitation
+	orgJobs     map[uuid.UUID]bool
+	dissolved   []uuid.UUID
+}
+
+func newFakeOrgRepo(members ...*domain.OrganizationMember) *fakeOrgRepo {
+	r := &fakeOrgRepo{
+		members:     make(map[uuid.UUID]*domain.OrganizationMember),
+		invitations: make(map[string]*domain.OrganizationInvitation),
+		orgJobs:     make(map[uuid.UUID]bool),
+	}
+	for _, member := range members {
+		r.members[member.ID] = member
+	}
+	return r
+}
+
+func (r *fakeOrgRepo) GetMembership(ctx context.Context, userID uuid.UUID) (*domain.OrganizationMember, error) {
+	for _, member := range r.members {
+		if member.UserID == userID {
+			copied := *member
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeOrgRepo) GetMember(ctx context.Context, orgID, memberID uuid.UUID) (*domain.OrganizationMember, error) {
+	member, ok := r.members[memberID]
+	if !ok || member.OrganizationID != orgID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *member
+	return &copied, nil
+}
+
+func (r *fakeOrgRepo) count(orgID uuid.UUID, role string) int64 {
+	var count int64
+	for _, member := range r.members {
+		if member.OrganizationID == orgID && (role == "" || member.Role == role) {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *fakeOrgRepo) CountOwners(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	return r.count(orgID, domain.OrgRoleOwner), nil
+}
+
+func (r *fakeOrgRepo) CountMembers(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	return r.count(orgID, ""), nil
+}
+
+func (r *fakeOrgRepo) HasJobs(ctx context.Context, orgID uuid.UUID) (bool, error) {
+	return r.orgJobs[orgID], nil
+}
+
+func (r *fakeOrgRepo) RemoveMember(ctx context.Context, memberID uuid.UUID) error {
+	delete(r.members, memberID)
+	return nil
+}
+
+func (r *fakeOrgRepo) Dissolve(ctx context.Context, orgID, memberID uuid.UUID) error {
+	delete(r.members, memberID)
+	r.dissolved = append(r.dissolved, orgID)
+	return nil
+}
+
+func (r *fakeOrgRepo) GetInvitationByHash(ctx context.Context, hash string) (*domain.OrganizationInvitation, error) {
+	invitation, ok := r.invitations[hash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return invitation, nil
+}
+
+func (r *fakeOrgRepo) AcceptInvitation(ctx context.Context, invitationID uuid.UUID, member, leaving *domain.OrganizationMember) error {
+	if leaving != nil {
+		if err := r.Dissolve(ctx, leaving.OrganizationID, leaving.ID); err != nil {
+			return err
+		}
+	}
+	member.ID = uuid.New()
+	r.members[member.ID] = member
+	return nil
+}
+
+type fakeJobRepo struct {
+	domain.JobRepository
+	jobs map[uuid.UUID]*domain.Job
+	// revisions hold each job's revisions, oldest first; tests may make them differ from the job.
+	revisions map[uuid.UUID][]domain.JobRevision
+	// beforeWrite runs ahead of each conditional write, standing in for a concurrent request.
+	beforeWrite func()
+	// applications counts each job's applications, soft-deleted ones included.
+	applications map[uuid.UUID]int
+}
+
+func newFakeJobRepo(jobs ...*domain.Job) *fakeJobRepo {
+	r := &fakeJobRepo{jobs: make(map[uuid.UUID]*domain.Job), revisions: make(map[uuid.UUID][]domain.JobRevision)}
+	for _, job := range jobs {
+		r.jobs[job.ID] = job
+	}
+	return r
+}
+
+func (r *fakeJobRepo) Create(ctx context.Context, job *domain.Job) error {
+	job.ID = uuid.New()
+	copied := *job
+	r.jobs[job.ID] = &copied
+	return nil
+}
+
+func (r *fakeJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	job, ok := r.jobs[id]
+	if !ok || job.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *fakeJobRepo) UpdateStatus(ctx context.Context, job *domain.Job, from string) error {
+	if r.beforeWrite != nil {
+		r.beforeWrite()
+	}
+	stored := r.jobs[job.ID]
+	if stored.Status != from {
+		return domain.ErrConflict
+	}
+	stored.Status, stored.PublishedAt, stored.ClosedAt = job.Status, job.PublishedAt, job.ClosedAt
+	return nil
+}
+
+func (r *fakeJobRepo) ListExpired(ctx context.Context, now time.Time) ([]domain.Job, error) {
+	var jobs []domain.Job
+	for _, job := range r.jobs {
+		if isOpenJob(job) && job.ExpiresAt != nil && !job.ExpiresAt.After(now) {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeJobRepo) CloseIfExpired(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	if r.beforeWrite != nil {
+		r.beforeWrite()
+	}
+	job := r.jobs[id]
+	if !isOpenJob(job) || job.ExpiresAt == nil || job.ExpiresAt.After(now) {
+		return false, nil
+	}
+	job.Status, job.ClosedAt = domain.JobStatusClosed, &now
+	return true, nil
+}
+
+func (r *fakeJobRepo) ListExpiringSoon(ctx context.Context, now, by time.Time) ([]domain.Job, error) {
+	var jobs []domain.Job
+	for _, job := range r.jobs {
+		if isOpenJob(job) && job.ExpiresAt != nil && job.ExpiresAt.After(now) && !job.ExpiresAt.After(by) && job.ExpiryReminderSentAt == nil {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeJobRepo) MarkExpiryReminderSent(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.jobs[id].ExpiryReminderSentAt = &at
+	return nil
+}
+
+func isOpenJob(job *domain.Job) bool {
+	return job.Status == domain.JobStatusPublished || job.Status == domain.JobStatusPaused
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+// fakeMailer fails for the addresses in failFor.
+type fakeMailer struct {
+	sent    []sentMail
+	failFor map[string]bool
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to, subject, body string) error {
+	if m.failFor[to] {
+		return errors.New("mail server unavailable")
+	}
+	m.sent = append(m.sent, sentMail{to: to, subject: subject, body: body})
+	return nil
+}
+
+func (r *fakeJobRepo) GetByIDWithDeleted(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *fakeJobRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	if job, ok := r.jobs[id]; ok && !job.DeletedAt.Valid {
+		job.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	}
+	return nil
+}
+
+func (r *fakeJobRepo) Restore(ctx context.Context, id uuid.UUID) error {
+	if job, ok := r.jobs[id]; ok {
+		job.DeletedAt = gorm.DeletedAt{}
+	}
+	return nil
+}
+
+func (r *fakeJobRepo) Purge(ctx context.Context, id uuid.UUID) error {
+	if r.applications[id] > 0 {
+		return domain.ErrConflict
+	}
+	delete(r.jobs, id)
+	return nil
+}
+
+func (r *fakeJobRepo) ListRevisions(ctx context.Context, jobID uuid.UUID) ([]domain.JobRevision, error) {
+	stored := r.revisions[jobID]
+	revisions := make([]domain.JobRevision, len(stored))
+	for i, revision := range stored {
+		revisions[len(stored)-1-i] = revision
+	}
+	return revisions, nil
+}
+
+func (r *fakeJobRepo) GetLatestRevision(ctx context.Context, jobID uuid.UUID) (*domain.JobRevision, error) {
+	stored := r.revisions[jobID]
+	if len(stored) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	revision := stored[len(stored)-1]
+	return &revision, nil
+}
+
+// addRevision appends a revision holding content to the job's history.
+func (r *fakeJobRepo) addRevision(jobID uuid.UUID, content domain.JobContent) domain.JobRevision {
+	revision := domain.JobRevision{ID: uuid.New(), JobID: jobID, Number: len(r.revisions[jobID]) + 1, Content: content}
+	r.revisions[jobID] = append(r.revisions[jobID], revision)
+	return revision
+}
+
+type fakeApplicationRepo struct {
+	domain.ApplicationRepository
+	created []*domain.Application
+}
+
+func (r *fakeApplicationRepo) Create(ctx context.Context, app *domain.Application) error {
+	r.created = append(r.created, app)
+	return nil
+}
+
+type fakeProfileRepo struct {
+	domain.ProfileRepository
+}
+
+func (r *fakeProfileRepo) GetSeekerProfile(ctx context.Context, userID uuid.UUID) (*domain.SeekerProfile, error) {
+	return nil, nil
+}
+
+func (r *fakeJobRepo) UpdateContent(ctx context.Context, job *domain.Job, editorID uuid.UUID) error {
+	stored, ok := r.jobs[job.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return domain.ErrNotFound
+	}
+	status, publishedAt, closedAt := stored.Status, stored.PublishedAt, stored.ClosedAt
+	*stored = *job
+	stored.Status, stored.PublishedAt, stored.ClosedAt = status, publishedAt, closedAt
+	r.addRevision(job.ID, job.Content())
+	return nil
+}
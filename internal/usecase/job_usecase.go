@@ -1,61 +1,177 @@
 package usecase
 
 import (
+	"be-job-portal/internal/config"
 	"be-job-portal/internal/domain"
 	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type jobUsecase struct {
-	jobRepo domain.JobRepository
+	jobRepo  domain.JobRepository
+	userRepo domain.UserRepository
+	orgRepo  domain.OrganizationRepository
+	mailer   domain.MailSender
+	audit    domain.AuditLogger
+	cfg      config.Config
 }
 
-func NewJobUsecase(jobRepo domain.JobRepository) domain.JobUsecase {
-	return &jobUsecase{jobRepo}
+func NewJobUsecase(jobRepo domain.JobRepository, userRepo domain.UserRepository, orgRepo domain.OrganizationRepository, mailer domain.MailSender, audit domain.AuditLogger, cfg config.Config) domain.JobUsecase {
+	return &jobUsecase{jobRepo, userRepo, orgRepo, mailer, audit, cfg}
 }
 
-func (u *jobUsecase) CreateJob(ctx context.Context, title, description, category, jobType, salary string, benefits []string, recruiterID uuid.UUID) error {
+func (u *jobUsecase) CreateJob(ctx context.Context, actor domain.Actor, input *domain.Job) error {
+	if actor.Role != domain.RoleRecruiter {
+		return domain.ErrForbidden
+	}
+	if err := validateJobDates(input, nil); err != nil {
+		return err
+	}
+	if err := domain.PrepareScreeningQuestions(input.Questions); err != nil {
+		return err
+	}
+
+	recruiter, err := u.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !recruiter.IsEmailVerified() {
+		return domain.ErrEmailNotVerified
+	}
+
+	actor, err = ensureMembership(ctx, u.orgRepo, u.userRepo, actor)
+	if err != nil {
+		return err
+	}
+	if err := domain.Authorize(actor, domain.ActionCreateJob, nil); err != nil {
+		return err
+	}
+
 	job := &domain.Job{
-		Title:       title,
-		Description: description,
-		Category:    category,
-		JobType:     jobType,
-		Salary:      salary,
-		Benefits:    benefits,
-		RecruiterID: recruiterID,
+		Status:         domain.JobStatusDraft,
+		RecruiterID:    actor.UserID,
+		OrganizationID: actor.Membership.OrganizationID,
 	}
+	applyJobInput(job, input)
 	return u.jobRepo.Create(ctx, job)
 }
 
-func (u *jobUsecase) UpdateJob(ctx context.Context, id, recruiterID uuid.UUID, title, description, category, jobType, salary string, benefits []string) error {
+func (u *jobUsecase) UpdateJob(ctx context.Context, actor domain.Actor, id uuid.UUID, input *domain.Job) error {
 	job, err := u.jobRepo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	actor, err = withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return err
+	}
+	if err := domain.Authorize(actor, domain.ActionUpdateJob, job); err != nil {
 		return err
 	}
-	if job == nil {
-		return domain.ErrNotFound
+	// Archived jobs are final, their content included.
+	if job.Status == domain.JobStatusArchived {
+		return domain.ErrConflict
 	}
+	if err := validateJobDates(input, job); err != nil {
+		return err
+	}
+	if err := domain.PrepareScreeningQuestions(input.Questions); err != nil {
+		return err
+	}
+
+	before := *job
+	applyJobInput(job, input)
 
-	if job.RecruiterID != recruiterID {
-		return domain.ErrUnauthorized
+	changes := auditDiff(before, job, domain.JobContentFields...)
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := u.jobRepo.UpdateContent(ctx, job, actor.UserID); err != nil {
+		return err
 	}
 
-	job.Title = title
-	job.Description = description
-	job.Category = category
-	job.JobType = jobType
-	job.Salary = salary
-	job.Benefits = benefits
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditJobUpdated,
+		ActorID:    &actor.UserID,
+		TargetType: domain.AuditTargetJob,
+		TargetID:   &job.ID,
+		Changes:    changes,
+	})
+	return nil
+}
 
-	return u.jobRepo.Update(ctx, job)
+// applyJobInput copies the fields a recruiter may edit.
+func applyJobInput(job, input *domain.Job) {
+	job.Title = input.Title
+	job.Description = input.Description
+	job.Category = input.Category
+	job.JobType = input.JobType
+	job.Salary = input.Salary
+	// The migrated free text is only there until the recruiter states a range.
+	if input.Salary.Min != nil {
+		job.SalaryLegacy = ""
+	}
+	job.Benefits = input.Benefits
+	job.Questions = input.Questions
+	job.RequiredSkills = domain.NormalizeSkills(input.RequiredSkills)
+	job.PreferredSkills = domain.NormalizeSkills(input.PreferredSkills)
+	job.MinExperienceYears = input.MinExperienceYears
+	job.MinEducation = input.MinEducation
+	job.ExpiresAt = input.ExpiresAt
+	job.ApplicationDeadline = input.ApplicationDeadline
 }
 
-func (u *jobUsecase) ListJobs(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedJobsResponse, error) {
-	jobs, totalCount, err := u.jobRepo.GetAll(ctx, params)
+// validateJobDates rejects a deadline after the expiry, and an expiry or deadline set
+// in the past. Dates left unchanged on an existing job are not checked against the clock.
+func validateJobDates(input, existing *domain.Job) error {
+	if input.ExpiresAt != nil && input.ApplicationDeadline != nil && input.ApplicationDeadline.After(*input.ExpiresAt) {
+		return domain.ErrBadRequest
+	}
+
+	now := time.Now()
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) && (existing == nil || !sameTime(existing.ExpiresAt, input.ExpiresAt)) {
+		return domain.ErrBadRequest
+	}
+	if input.ApplicationDeadline != nil && !input.ApplicationDeadline.After(now) && (existing == nil || !sameTime(existing.ApplicationDeadline, input.ApplicationDeadline)) {
+		return domain.ErrBadRequest
+	}
+	return nil
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func (u *jobUsecase) ListJobs(ctx context.Context, filter domain.JobFilter) (*domain.PaginatedJobsResponse, error) {
+	if filter.Sort == "" {
+		filter.Sort = domain.JobSortNewest
+	}
+	if !domain.IsValidJobSort(filter.Sort) {
+		return nil, domain.ErrBadRequest
+	}
+	params := filter.PaginationParams
+
+	jobs, totalCount, err := u.jobRepo.GetAll(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
+	for i := range jobs {
+		jobs[i].Questions = domain.WithoutKnockouts(jobs[i].Questions)
+	}
 
 	totalPages := int(totalCount) / params.Limit
 	if int(totalCount)%params.Limit != 0 {
@@ -74,13 +190,342 @@ func (u *jobUsecase) ListJobs(ctx context.Context, params domain.PaginationParam
 	return &domain.PaginatedJobsResponse{
 		Jobs:       jobs,
 		Pagination: paginationMeta,
+		Filters:    filter,
+	}, nil
+}
+
+func (u *jobUsecase) SearchJobs(ctx context.Context, filter domain.JobFilter) (*domain.JobSearchResponse, error) {
+	if filter.Query == "" {
+		return nil, domain.ErrBadRequest
+	}
+	if filter.Sort == "" {
+		filter.Sort = domain.JobSortRelevance
+	}
+	if !domain.IsValidJobSort(filter.Sort) {
+		return nil, domain.ErrBadRequest
+	}
+
+	results, totalCount, err := u.jobRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].TitleHighlight = renderHighlight(results[i].TitleHighlight)
+		results[i].Snippet = renderHighlight(results[i].Snippet)
+		results[i].Questions = domain.WithoutKnockouts(results[i].Questions)
+	}
+
+	params := filter.PaginationParams
+	totalPages := int(totalCount) / params.Limit
+	if int(totalCount)%params.Limit != 0 {
+		totalPages++
+	}
+
+	return &domain.JobSearchResponse{
+		Results: results,
+		Pagination: domain.PaginationMeta{
+			CurrentPage:  params.Page,
+			TotalPages:   totalPages,
+			TotalItems:   totalCount,
+			ItemsPerPage: params.Limit,
+			HasNext:      params.Page < totalPages,
+			HasPrev:      params.Page > 1,
+		},
+		Filters: filter,
 	}, nil
 }
 
-func (u *jobUsecase) GetJob(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
-	return u.jobRepo.GetByID(ctx, id)
+// renderHighlight escapes posting text for HTML and turns the search markers into <mark> tags.
+func renderHighlight(s string) string {
+	return strings.NewReplacer(domain.HighlightStart, "<mark>", domain.HighlightStop, "</mark>").Replace(html.EscapeString(s))
+}
+
+// GetJob hides drafts and archived jobs from anyone outside the organization, as if
+// they did not exist, and shows outsiders the screening questions without the knockout
+// rules.
+func (u *jobUsecase) GetJob(ctx context.Context, actor domain.Actor, id uuid.UUID) (*domain.Job, error) {
+	job, err := u.jobRepo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err = withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsPublic() {
+		if err := domain.Authorize(actor, domain.ActionViewUnpublishedJob, job); err != nil {
+			return nil, domain.ErrNotFound
+		}
+	}
+	if err := domain.Authorize(actor, domain.ActionViewKnockoutRules, job); err != nil {
+		job.Questions = domain.WithoutKnockouts(job.Questions)
+	}
+	return job, nil
+}
+
+func (u *jobUsecase) DeleteJob(ctx context.Context, actor domain.Actor, id uuid.UUID) error {
+	job, err := u.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	actor, err = withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return err
+	}
+	if err := domain.Authorize(actor, domain.ActionDeleteJob, job); err != nil {
+		return err
+	}
+
+	if err := u.jobRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditJobDeleted,
+		ActorID:    &actor.UserID,
+		TargetType: domain.AuditTargetJob,
+		TargetID:   &job.ID,
+	})
+	return nil
+}
+
+func (u *jobUsecase) RestoreJob(ctx context.Context, actor domain.Actor, id uuid.UUID) (*domain.Job, error) {
+	job, err := u.jobRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	actor, err = withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionDeleteJob, job); err != nil {
+		return nil, err
+	}
+	if !job.DeletedAt.Valid {
+		return job, nil
+	}
+
+	if err := u.jobRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditJobRestored,
+		ActorID:    &actor.UserID,
+		TargetType: domain.AuditTargetJob,
+		TargetID:   &job.ID,
+	})
+
+	job.DeletedAt = gorm.DeletedAt{}
+	return job, nil
+}
+
+// PurgeJob deletes a job permanently. Jobs with applications are refused with
+// ErrConflict so candidate history is never lost; they can only be soft-deleted.
+func (u *jobUsecase) PurgeJob(ctx context.Context, actor domain.Actor, id uuid.UUID) error {
+	if err := domain.Authorize(actor, domain.ActionPurgeJob, nil); err != nil {
+		return err
+	}
+
+	job, err := u.jobRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+
+	if err := u.jobRepo.Purge(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditJobPurged,
+		ActorID:    &actor.UserID,
+		TargetType: domain.AuditTargetJob,
+		TargetID:   &job.ID,
+		Metadata:   map[string]string{"title": job.Title, "organization_id": job.OrganizationID.String()},
+	})
+	return nil
+}
+
+func (u *jobUsecase) ListRevisions(ctx context.Context, actor domain.Actor, id uuid.UUID) ([]domain.JobRevisionDiff, error) {
+	job, err := u.jobRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	actor, err = withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionViewJobRevisions, job); err != nil {
+		return nil, err
+	}
+
+	revisions, err := u.jobRepo.ListRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]domain.JobRevisionDiff, len(revisions))
+	for i, revision := range revisions {
+		diffs[i].JobRevision = revision
+		// Revisions come newest first, so the previous one is next in the list.
+		if i+1 < len(revisions) {
+			diffs[i].Changes = auditDiff(revisions[i+1].Content, revision.Content, domain.JobContentFields...)
+		}
+	}
+	return diffs, nil
+}
+
+func (u *jobUsecase) ChangeJobStatus(ctx context.Context, actor domain.Actor, id uuid.UUID, status string) (*domain.Job, error) {
+	if !domain.IsValidJobStatus(status) {
+		return nil, domain.ErrBadRequest
+	}
+
+	job, err := u.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	actor, err = withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionUpdateJob, job); err != nil {
+		return nil, err
+	}
+
+	if job.Status == status {
+		return job, nil
+	}
+	if !domain.CanTransitionJob(job.Status, status) {
+		return nil, domain.ErrConflict
+	}
+
+	before := job.Status
+	now := time.Now()
+	switch status {
+	case domain.JobStatusPublished:
+		if job.IsExpired(now) {
+			return nil, domain.ErrJobExpired
+		}
+		// Reopening keeps the original publication date.
+		if job.PublishedAt == nil {
+			job.PublishedAt = &now
+		}
+		job.ClosedAt = nil
+	case domain.JobStatusClosed:
+		job.ClosedAt = &now
+	}
+	job.Status = status
+
+	// A concurrent status change, including the scheduler closing the job, wins.
+	if err := u.jobRepo.UpdateStatus(ctx, job, before); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditJobStatusChanged,
+		ActorID:    &actor.UserID,
+		TargetType: domain.AuditTargetJob,
+		TargetID:   &job.ID,
+		Changes:    map[string]domain.AuditChange{"status": {Before: before, After: status}},
+	})
+	return job, nil
 }
 
-func (u *jobUsecase) ListJobsByRecruiter(ctx context.Context, recruiterID uuid.UUID) ([]domain.Job, error) {
+func (u *jobUsecase) ListJobsByRecruiter(ctx context.Context, actor domain.Actor, recruiterID uuid.UUID) ([]domain.Job, error) {
+	if err := domain.Authorize(actor, domain.ActionListRecruiterJobs, domain.RecruiterRef{ID: recruiterID}); err != nil {
+		return nil, err
+	}
 	return u.jobRepo.GetByRecruiterID(ctx, recruiterID)
 }
+
+// ListOrganizationJobs lists every job posted by the caller's organization.
+func (u *jobUsecase) ListOrganizationJobs(ctx context.Context, actor domain.Actor) ([]domain.Job, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Membership == nil {
+		return []domain.Job{}, nil
+	}
+	if err := domain.Authorize(actor, domain.ActionViewOrganization, nil); err != nil {
+		return nil, err
+	}
+	return u.jobRepo.GetByOrganizationID(ctx, actor.Membership.OrganizationID)
+}
+
+// CloseExpiredJobs closes published and paused jobs whose expiry has passed.
+func (u *jobUsecase) CloseExpiredJobs(ctx context.Context) error {
+	now := time.Now()
+	jobs, err := u.jobRepo.ListExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		closed, err := u.jobRepo.CloseIfExpired(ctx, job.ID, now)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			continue
+		}
+		recordAudit(ctx, u.audit, domain.AuditEvent{
+			Action:     domain.AuditJobStatusChanged,
+			TargetType: domain.AuditTargetJob,
+			TargetID:   &job.ID,
+			Changes:    map[string]domain.AuditChange{"status": {Before: job.Status, After: domain.JobStatusClosed}},
+			Metadata:   map[string]string{"reason": "expired"},
+		})
+	}
+	return nil
+}
+
+// SendExpiryReminders emails the recruiter of each job expiring within
+// JobExpiryReminder, once per expiry date. A failed send is retried on the next run.
+func (u *jobUsecase) SendExpiryReminders(ctx context.Context) error {
+	now := time.Now()
+	jobs, err := u.jobRepo.ListExpiringSoon(ctx, now, now.Add(u.cfg.JobExpiryReminder))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, job := range jobs {
+		if job.Recruiter == nil {
+			continue
+		}
+		link := fmt.Sprintf("%s/jobs/%s", u.cfg.AppBaseURL, job.ID)
+		body := fmt.Sprintf("Your job posting %q closes automatically on %s.\n\nTo keep it open, edit the posting and move its expiry date:\n%s", job.Title, job.ExpiresAt.UTC().Format(time.RFC1123), link)
+		if err := u.mailer.Send(ctx, job.Recruiter.Email, "Your job posting \""+job.Title+"\" is about to expire", body); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := u.jobRepo.MarkExpiryReminderSent(ctx, job.ID, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,388 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"be-job-portal/internal/config"
+	"be-job-portal/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestChangeJobStatus(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+	editor := newMember(orgID, domain.OrgRoleRecruiter)
+	published := time.Now().Add(-time.Hour)
+	job := &domain.Job{ID: uuid.New(), OrganizationID: orgID, Status: domain.JobStatusPublished, PublishedAt: &published}
+	jobs := newFakeJobRepo(job)
+	audit := &fakeAuditLogger{}
+	u := NewJobUsecase(jobs, nil, newFakeOrgRepo(editor), nil, audit, config.Config{})
+
+	got, err := u.ChangeJobStatus(ctx, recruiterActor(editor), job.ID, domain.JobStatusClosed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := jobs.jobs[job.ID]
+	if got.Status != domain.JobStatusClosed || stored.Status != domain.JobStatusClosed || stored.ClosedAt == nil {
+		t.Fatalf("stored job = %+v", stored)
+	}
+	if stored.PublishedAt == nil || !stored.PublishedAt.Equal(published) {
+		t.Fatalf("closing changed published_at to %v", stored.PublishedAt)
+	}
+	if got := audit.actions(); len(got) != 1 || got[0] != domain.AuditJobStatusChanged {
+		t.Fatalf("audit actions = %v", got)
+	}
+}
+
+func TestChangeJobStatusLosesToConcurrentChange(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+	editor := newMember(orgID, domain.OrgRoleRecruiter)
+	job := &domain.Job{ID: uuid.New(), OrganizationID: orgID, Status: domain.JobStatusPublished}
+	jobs := newFakeJobRepo(job)
+	closedAt := time.Now()
+	// The scheduler closes the job between the read and the write.
+	jobs.beforeWrite = func() {
+		jobs.jobs[job.ID].Status = domain.JobStatusClosed
+		jobs.jobs[job.ID].ClosedAt = &closedAt
+	}
+	audit := &fakeAuditLogger{}
+	u := NewJobUsecase(jobs, nil, newFakeOrgRepo(editor), nil, audit, config.Config{})
+
+	if _, err := u.ChangeJobStatus(ctx, recruiterActor(editor), job.ID, domain.JobStatusPaused); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("err = %v, want ErrConflict", err)
+	}
+	if stored := jobs.jobs[job.ID]; stored.Status != domain.JobStatusClosed || stored.ClosedAt != &closedAt {
+		t.Fatalf("stale status change overwrote the closed job: %+v", stored)
+	}
+	if got := audit.actions(); len(got) != 0 {
+		t.Fatalf("audited a status change that did not happen: %v", got)
+	}
+}
+
+func TestCloseExpiredJobs(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	expired := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPublished, ExpiresAt: &past}
+	pausedExpired := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPaused, ExpiresAt: &past}
+	extended := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPublished, ExpiresAt: &past}
+	live := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPublished, ExpiresAt: &future}
+	draft := &domain.Job{ID: uuid.New(), Status: domain.JobStatusDraft, ExpiresAt: &past}
+	jobs := newFakeJobRepo(expired, pausedExpired, extended, live, draft)
+	// The recruiter moves one expiry out after the scheduler listed it.
+	jobs.beforeWrite = func() { extended.ExpiresAt = &future }
+	audit := &fakeAuditLogger{}
+	u := NewJobUsecase(jobs, nil, nil, nil, audit, config.Config{})
+
+	if err := u.CloseExpiredJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := map[*domain.Job]string{
+		expired:       domain.JobStatusClosed,
+		pausedExpired: domain.JobStatusClosed,
+		extended:      domain.JobStatusPublished,
+		live:          domain.JobStatusPublished,
+		draft:         domain.JobStatusDraft,
+	}
+	for job, status := range want {
+		if job.Status != status {
+			t.Errorf("job %s is %s, want %s", job.ID, job.Status, status)
+		}
+	}
+	if got := audit.actions(); len(got) != 2 {
+		t.Fatalf("audit actions = %v, want one per closed job", got)
+	}
+	for _, event := range audit.events {
+		if event.ActorID != nil || event.Metadata["reason"] != "expired" {
+			t.Fatalf("expiry event = %+v, want no actor and reason expired", event)
+		}
+	}
+}
+
+func TestSendExpiryReminders(t *testing.T) {
+	ctx := context.Background()
+	soon := time.Now().Add(time.Hour)
+	later := time.Now().Add(30 * 24 * time.Hour)
+	sentAt := time.Now().Add(-time.Hour)
+	recruiter := func(email string) *domain.User { return &domain.User{ID: uuid.New(), Email: email} }
+
+	due := &domain.Job{ID: uuid.New(), Title: "Go Developer", Status: domain.JobStatusPublished, ExpiresAt: &soon, Recruiter: recruiter("ada@example.com")}
+	failing := &domain.Job{ID: uuid.New(), Title: "SRE", Status: domain.JobStatusPaused, ExpiresAt: &soon, Recruiter: recruiter("down@example.com")}
+	reminded := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPublished, ExpiresAt: &soon, ExpiryReminderSentAt: &sentAt, Recruiter: recruiter("grace@example.com")}
+	notYet := &domain.Job{ID: uuid.New(), Status: domain.JobStatusPublished, ExpiresAt: &later, Recruiter: recruiter("linus@example.com")}
+	jobs := newFakeJobRepo(due, failing, reminded, notYet)
+	mailer := &fakeMailer{failFor: map[string]bool{"down@example.com": true}}
+	u := NewJobUsecase(jobs, nil, nil, mailer, &fakeAuditLogger{}, config.Config{JobExpiryReminder: 72 * time.Hour, AppBaseURL: "https://jobs.example.com"})
+
+	if err := u.SendExpiryReminders(ctx); err == nil {
+		t.Fatal("a failed send was not reported")
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].to != "ada@example.com" {
+		t.Fatalf("sent = %+v, want one reminder to ada@example.com", mailer.sent)
+	}
+	if !strings.Contains(mailer.sent[0].body, "https://jobs.example.com/jobs/"+due.ID.String()) {
+		t.Fatalf("reminder does not link the job: %q", mailer.sent[0].body)
+	}
+	if due.ExpiryReminderSentAt == nil {
+		t.Fatal("sent reminder was not recorded")
+	}
+	if failing.ExpiryReminderSentAt != nil {
+		t.Fatal("failed reminder was recorded, so it will not be retried")
+	}
+
+	// The next run retries only the failed one.
+	mailer.failFor = nil
+	if err := u.SendExpiryReminders(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.sent) != 2 || mailer.sent[1].to != "down@example.com" {
+		t.Fatalf("sent = %+v, want the failed reminder retried once", mailer.sent)
+	}
+}
+
+func TestListRevisions(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+	viewer := newMember(orgID, domain.OrgRoleViewer)
+	outsider := newMember(uuid.New(), domain.OrgRoleOwner)
+	job := &domain.Job{ID: uuid.New(), OrganizationID: orgID, Status: domain.JobStatusPublished, Title: "Go Engineer", Benefits: []string{"Remote"}}
+	jobs := newFakeJobRepo(job)
+
+	first := job.Content()
+	jobs.addRevision(job.ID, first)
+	second := first
+	second.Title = "Senior Go Engineer"
+	jobs.addRevision(job.ID, second)
+	third := second
+	third.Benefits = []string{"Remote", "Stock options"}
+	third.MinExperienceYears = 5
+	jobs.addRevision(job.ID, third)
+
+	u := NewJobUsecase(jobs, nil, newFakeOrgRepo(viewer, outsider), nil, &fakeAuditLogger{}, config.Config{})
+
+	diffs, err := u.ListRevisions(ctx, recruiterActor(viewer), job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 3 || diffs[0].Number != 3 || diffs[2].Number != 1 {
+		t.Fatalf("got revisions %v, want 3, 2, 1", revisionNumbers(diffs))
+	}
+
+	if got := changedFields(diffs[0].Changes); got != "benefits,min_experience_years" {
+		t.Fatalf("revision 3 changed %q", got)
+	}
+	if change := diffs[0].Changes["min_experience_years"]; change.Before != float64(0) || change.After != float64(5) {
+		t.Fatalf("min_experience_years change = %+v", change)
+	}
+	if got := changedFields(diffs[1].Changes); got != "title" {
+		t.Fatalf("revision 2 changed %q", got)
+	}
+	if change := diffs[1].Changes["title"]; change.Before != "Go Engineer" || change.After != "Senior Go Engineer" {
+		t.Fatalf("title change = %+v", change)
+	}
+	if diffs[2].Changes != nil {
+		t.Fatalf("the first revision has changes %v", diffs[2].Changes)
+	}
+
+	if _, err := u.ListRevisions(ctx, recruiterActor(outsider), job.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("another organization: err = %v, want ErrForbidden", err)
+	}
+	seeker := domain.Actor{UserID: uuid.New(), Role: domain.RoleSeeker}
+	if _, err := u.ListRevisions(ctx, seeker, job.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("seeker: err = %v, want ErrForbidden", err)
+	}
+	if _, err := u.ListRevisions(ctx, recruiterActor(viewer), uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown job: err = %v, want ErrNotFound", err)
+	}
+}
+
+func revisionNumbers(diffs []domain.JobRevisionDiff) []int {
+	numbers := make([]int, len(diffs))
+	for i, diff := range diffs {
+		numbers[i] = diff.Number
+	}
+	return numbers
+}
+
+// changedFields lists the changed field names, sorted and joined by commas.
+func changedFields(changes map[string]domain.AuditChange) string {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ",")
+}
+
+func TestUpdateJob(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+	editor := newMember(orgID, domain.OrgRoleRecruiter)
+
+	for _, status := range []string{domain.JobStatusDraft, domain.JobStatusPublished, domain.JobStatusPaused, domain.JobStatusClosed, domain.JobStatusArchived} {
+		t.Run(status, func(t *testing.T) {
+			job := &domain.Job{ID: uuid.New(), OrganizationID: orgID, Status: status, Title: "Go Engineer"}
+			jobs := newFakeJobRepo(job)
+			u := NewJobUsecase(jobs, nil, newFakeOrgRepo(editor), nil, &fakeAuditLogger{}, config.Config{})
+
+			err := u.UpdateJob(ctx, recruiterActor(editor), job.ID, &domain.Job{Title: "Senior Go Engineer"})
+			if status == domain.JobStatusArchived {
+				if !errors.Is(err, domain.ErrConflict) {
+					t.Fatalf("err = %v, want ErrConflict", err)
+				}
+				if title := jobs.jobs[job.ID].Title; title != "Go Engineer" || len(jobs.revisions[job.ID]) != 0 {
+					t.Fatalf("archived job was edited: title %q, %d revisions", title, len(jobs.revisions[job.ID]))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored := jobs.jobs[job.ID]; stored.Title != "Senior Go Engineer" || stored.Status != status {
+				t.Fatalf("stored job = %+v", stored)
+			}
+		})
+	}
+}
+
+func TestCreateJobRequiresVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name       string
+		role       string
+		verifiedAt *time.Time
+		wantErr    error
+	}{
+		{name: "verified recruiter", role: domain.RoleRecruiter, verifiedAt: &verifiedAt},
+		{name: "unverified recruiter", role: domain.RoleRecruiter, wantErr: domain.ErrEmailNotVerified},
+		{name: "seeker", role: domain.RoleSeeker, verifiedAt: &verifiedAt, wantErr: domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &domain.User{ID: uuid.New(), Email: "ada@example.com", Role: tt.role, EmailVerifiedAt: tt.verifiedAt}
+			member := &domain.OrganizationMember{ID: uuid.New(), OrganizationID: uuid.New(), UserID: user.ID, Role: domain.OrgRoleOwner}
+			jobs := newFakeJobRepo()
+			u := NewJobUsecase(jobs, newFakeUserRepo(user), newFakeOrgRepo(member), nil, &fakeAuditLogger{}, config.Config{})
+
+			err := u.CreateJob(ctx, domain.Actor{UserID: user.ID, Role: tt.role}, &domain.Job{Title: "Go Engineer"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if created := len(jobs.jobs) == 1; created != (tt.wantErr == nil) {
+				t.Fatalf("job created = %v", created)
+			}
+		})
+	}
+}
+
+func TestDeleteAndRestoreJob(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+	owner := newMember(orgID, domain.OrgRoleRecruiter)
+	job := &domain.Job{ID: uuid.New(), OrganizationID: orgID, Status: domain.JobStatusPublished, Title: "Go Engineer"}
+	jobs := newFakeJobRepo(job)
+	audit := &fakeAuditLogger{}
+	u := NewJobUsecase(jobs, nil, newFakeOrgRepo(owner), nil, audit, config.Config{})
+	seeker := domain.Actor{UserID: uuid.New(), Role: domain.RoleSeeker}
+	outsider := recruiterActor(newMember(uuid.New(), domain.OrgRoleOwner))
+
+	if err := u.DeleteJob(ctx, outsider, job.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("delete by another organization: err = %v, want ErrForbidden", err)
+	}
+	if err := u.DeleteJob(ctx, recruiterActor(owner), job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.GetJob(ctx, seeker, job.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("deleted job: err = %v, want ErrNotFound", err)
+	}
+	if err := u.UpdateJob(ctx, recruiterActor(owner), job.ID, &domain.Job{Title: "Senior Go Engineer"}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("editing a deleted job: err = %v, want ErrNotFound", err)
+	}
+	if err := u.DeleteJob(ctx, recruiterActor(owner), job.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("deleting twice: err = %v, want ErrNotFound", err)
+	}
+
+	if _, err := u.RestoreJob(ctx, outsider, job.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("restore by another organization: err = %v, want ErrForbidden", err)
+	}
+	restored, err := u.RestoreJob(ctx, recruiterActor(owner), job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt.Valid || restored.Status != domain.JobStatusPublished {
+		t.Fatalf("restored job = %+v", restored)
+	}
+	got, err := u.GetJob(ctx, seeker, job.ID)
+	if err != nil || got.Title != "Go Engineer" {
+		t.Fatalf("restored job is not listed: %+v, %v", got, err)
+	}
+
+	// Restoring a live job changes nothing.
+	if _, err := u.RestoreJob(ctx, recruiterActor(owner), job.ID); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{domain.AuditJobDeleted, domain.AuditJobRestored}
+	if got := audit.actions(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("audit = %v, want %v", got, want)
+	}
+}
+
+func TestPurgeJob(t *testing.T) {
+	ctx := context.Background()
+	admin := domain.Actor{UserID: uuid.New(), Role: domain.RoleAdmin}
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name         string
+		actor        domain.Actor
+		deleted      bool
+		applications int
+		wantErr      error
+	}{
+		{name: "live job without applications", actor: admin},
+		{name: "deleted job without applications", actor: admin, deleted: true},
+		{name: "deleted job with applications", actor: admin, deleted: true, applications: 2, wantErr: domain.ErrConflict},
+		{name: "live job with applications", actor: admin, applications: 1, wantErr: domain.ErrConflict},
+		{name: "recruiter", actor: recruiterActor(newMember(uuid.New(), domain.OrgRoleOwner)), deleted: true, wantErr: domain.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &domain.Job{ID: uuid.New(), OrganizationID: uuid.New(), Status: domain.JobStatusClosed, Title: "Go Engineer"}
+			if tt.deleted {
+				job.DeletedAt = deletedAt
+			}
+			jobs := newFakeJobRepo(job)
+			jobs.applications = map[uuid.UUID]int{job.ID: tt.applications}
+			audit := &fakeAuditLogger{}
+			u := NewJobUsecase(jobs, nil, newFakeOrgRepo(), nil, audit, config.Config{})
+
+			err := u.PurgeJob(ctx, tt.actor, job.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			_, kept := jobs.jobs[job.ID]
+			if kept != (tt.wantErr != nil) {
+				t.Fatalf("job kept = %v", kept)
+			}
+			purged := len(audit.actions()) == 1 && audit.actions()[0] == domain.AuditJobPurged
+			if purged == kept {
+				t.Fatalf("audit = %v", audit.actions())
+			}
+		})
+	}
+
+	if err := NewJobUsecase(newFakeJobRepo(), nil, nil, nil, &fakeAuditLogger{}, config.Config{}).PurgeJob(ctx, admin, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown job: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"be-job-portal/internal/domain"
+)
+
+// loginThrottle locks a key once it reaches maxFailures failed attempts within the
+// window. Each further failure doubles the lockout, up to maxLockout.
+type loginThrottle struct {
+	store       domain.LoginAttemptRepository
+	maxFailures int
+	lockout     time.Duration
+	maxLockout  time.Duration
+	window      time.Duration
+}
+
+// check returns a RetryAfterError if the key is currently locked.
+func (t *loginThrottle) check(ctx context.Context, key string) error {
+	attempt, err := t.store.Get(ctx, key)
+	if err != nil || attempt == nil || attempt.LockedUntil == nil {
+		return err
+	}
+
+	if remaining := time.Until(*attempt.LockedUntil); remaining > 0 {
+		return &domain.RetryAfterError{RetryAfter: remaining}
+	}
+	return nil
+}
+
+func (t *loginThrottle) fail(ctx context.Context, key string) error {
+	now := time.Now()
+	attempt, err := t.store.RecordFailure(ctx, key, now, t.window)
+	if err != nil {
+		return err
+	}
+	if attempt.Failures < t.maxFailures {
+		return nil
+	}
+
+	return t.store.Lock(ctx, key, now.Add(t.lockoutFor(attempt.Failures)))
+}
+
+func (t *loginThrottle) reset(ctx context.Context, key string) error {
+	return t.store.Reset(ctx, key)
+}
+
+func (t *loginThrottle) lockoutFor(failures int) time.Duration {
+	d := t.lockout
+	for i := t.maxFailures; i < failures && d < t.maxLockout; i++ {
+		d *= 2
+	}
+	if d > t.maxLockout {
+		d = t.maxLockout
+	}
+	return d
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginThrottleLockoutFor(t *testing.T) {
+	throttle := &loginThrottle{maxFailures: 3, lockout: time.Minute, maxLockout: time.Hour}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 3, want: time.Minute},
+		{failures: 4, want: 2 * time.Minute},
+		{failures: 5, want: 4 * time.Minute},
+		{failures: 8, want: 32 * time.Minute},
+		{failures: 9, want: time.Hour},
+		{failures: 50, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := throttle.lockoutFor(tt.failures); got != tt.want {
+			t.Errorf("lockoutFor(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"be-job-portal/internal/config"
+	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type organizationUsecase struct {
+	orgRepo  domain.OrganizationRepository
+	userRepo domain.UserRepository
+	mailer   domain.MailSender
+	cfg      config.Config
+}
+
+func NewOrganizationUsecase(orgRepo domain.OrganizationRepository, userRepo domain.UserRepository, mailer domain.MailSender, cfg config.Config) domain.OrganizationUsecase {
+	return &organizationUsecase{
+		orgRepo:  orgRepo,
+		userRepo: userRepo,
+		mailer:   mailer,
+		cfg:      cfg,
+	}
+}
+
+func (u *organizationUsecase) CreateOrganization(ctx context.Context, actor domain.Actor, name string) (*domain.OrganizationMember, error) {
+	if actor.Role != domain.RoleRecruiter {
+		return nil, domain.ErrForbidden
+	}
+
+	existing, err := u.orgRepo.GetMembership(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, domain.ErrConflict
+	}
+
+	org := &domain.Organization{Name: name}
+	member := &domain.OrganizationMember{UserID: actor.UserID, Role: domain.OrgRoleOwner}
+	if err := u.orgRepo.Create(ctx, org, member); err != nil {
+		return nil, err
+	}
+	member.Organization = org
+	return member, nil
+}
+
+func (u *organizationUsecase) GetMyOrganization(ctx context.Context, actor domain.Actor) (*domain.OrganizationMember, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Membership == nil {
+		return nil, domain.ErrNotFound
+	}
+	return actor.Membership, nil
+}
+
+func (u *organizationUsecase) UpdateOrganization(ctx context.Context, actor domain.Actor, name string) (*domain.Organization, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionManageOrganization, nil); err != nil {
+		return nil, err
+	}
+
+	org := actor.Membership.Organization
+	org.Name = name
+	if err := u.orgRepo.Update(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (u *organizationUsecase) ListMembers(ctx context.Context, actor domain.Actor) ([]domain.OrganizationMember, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionViewOrganization, nil); err != nil {
+		return nil, err
+	}
+	return u.orgRepo.ListMembers(ctx, actor.Membership.OrganizationID)
+}
+
+func (u *organizationUsecase) ChangeMemberRole(ctx context.Context, actor domain.Actor, memberID uuid.UUID, role string) error {
+	actor, target, err := u.loadTarget(ctx, actor, memberID)
+	if err != nil {
+		return err
+	}
+	if err := domain.Authorize(actor, domain.ActionManageMember, target); err != nil {
+		return err
+	}
+	if role == domain.OrgRoleOwner {
+		if err := domain.Authorize(actor, domain.ActionGrantOwner, nil); err != nil {
+			return err
+		}
+	}
+	if target.Role == role {
+		return nil
+	}
+	if target.Role == domain.OrgRoleOwner {
+		if err := u.ensureAnotherOwner(ctx, target.OrganizationID); err != nil {
+			return err
+		}
+	}
+
+	return u.orgRepo.UpdateMemberRole(ctx, target.ID, role)
+}
+
+// RemoveMember removes a teammate, or lets any member leave when memberID is their own.
+// The last member leaving dissolves the organization.
+func (u *organizationUsecase) RemoveMember(ctx context.Context, actor domain.Actor, memberID uuid.UUID) error {
+	actor, target, err := u.loadTarget(ctx, actor, memberID)
+	if err != nil {
+		return err
+	}
+	if target.UserID != actor.UserID {
+		if err := domain.Authorize(actor, domain.ActionManageMember, target); err != nil {
+			return err
+		}
+	} else {
+		members, err := u.orgRepo.CountMembers(ctx, target.OrganizationID)
+		if err != nil {
+			return err
+		}
+		if members == 1 {
+			return u.orgRepo.Dissolve(ctx, target.OrganizationID, target.ID)
+		}
+	}
+	if target.Role == domain.OrgRoleOwner {
+		if err := u.ensureAnotherOwner(ctx, target.OrganizationID); err != nil {
+			return err
+		}
+	}
+
+	return u.orgRepo.RemoveMember(ctx, target.ID)
+}
+
+func (u *organizationUsecase) Invite(ctx context.Context, actor domain.Actor, email, role string) (*domain.OrganizationInvitation, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionManageOrganization, nil); err != nil {
+		return nil, err
+	}
+	if role == domain.OrgRoleOwner {
+		if err := domain.Authorize(actor, domain.ActionGrantOwner, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	rawToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	invitation := &domain.OrganizationInvitation{
+		OrganizationID: actor.Membership.OrganizationID,
+		Email:          strings.ToLower(email),
+		Role:           role,
+		TokenHash:      utils.HashToken(rawToken),
+		InvitedByID:    actor.UserID,
+		ExpiresAt:      time.Now().Add(u.cfg.OrgInviteTTL),
+	}
+	if err := u.orgRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	link := fmt.Sprintf("%s/invitations/accept?token=%s", u.cfg.AppBaseURL, rawToken)
+	body := fmt.Sprintf("You have been invited to join %s on the job portal as %s.\n\nSign in with a recruiter account using this email address and open the link below within %s to accept:\n%s", actor.Membership.Organization.Name, strings.ToLower(role), u.cfg.OrgInviteTTL, link)
+	if err := u.mailer.Send(ctx, invitation.Email, "You're invited to join "+actor.Membership.Organization.Name, body); err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (u *organizationUsecase) ListInvitations(ctx context.Context, actor domain.Actor) ([]domain.OrganizationInvitation, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(actor, domain.ActionManageOrganization, nil); err != nil {
+		return nil, err
+	}
+	return u.orgRepo.ListPendingInvitations(ctx, actor.Membership.OrganizationID, time.Now())
+}
+
+func (u *organizationUsecase) RevokeInvitation(ctx context.Context, actor domain.Actor, invitationID uuid.UUID) error {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return err
+	}
+	if err := domain.Authorize(actor, domain.ActionManageOrganization, nil); err != nil {
+		return err
+	}
+	return u.orgRepo.DeleteInvitation(ctx, actor.Membership.OrganizationID, invitationID)
+}
+
+// AcceptInvitation joins the invited organization. The signed-in account must use the
+// invited address and not already belong to a team, other than an untouched personal
+// organization, which is dissolved on the way.
+func (u *organizationUsecase) AcceptInvitation(ctx context.Context, actor domain.Actor, token string) (*domain.OrganizationMember, error) {
+	invitation, err := u.orgRepo.GetInvitationByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if !invitation.IsPending(time.Now()) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	user, err := u.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != domain.RoleRecruiter || !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, domain.ErrForbidden
+	}
+
+	existing, err := u.orgRepo.GetMembership(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		personal, err := u.isPersonalOrganization(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+		if !personal || existing.OrganizationID == invitation.OrganizationID {
+			return nil, domain.ErrConflict
+		}
+	}
+
+	member := &domain.OrganizationMember{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         actor.UserID,
+		Role:           invitation.Role,
+	}
+	if err := u.orgRepo.AcceptInvitation(ctx, invitation.ID, member, existing); err != nil {
+		return nil, err
+	}
+	return u.orgRepo.GetMembership(ctx, actor.UserID)
+}
+
+func (u *organizationUsecase) loadTarget(ctx context.Context, actor domain.Actor, memberID uuid.UUID) (domain.Actor, *domain.OrganizationMember, error) {
+	actor, err := withMembership(ctx, u.orgRepo, actor)
+	if err != nil {
+		return actor, nil, err
+	}
+	if actor.Membership == nil {
+		return actor, nil, domain.ErrForbidden
+	}
+
+	target, err := u.orgRepo.GetMember(ctx, actor.Membership.OrganizationID, memberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return actor, nil, domain.ErrNotFound
+		}
+		return actor, nil, err
+	}
+	return actor, target, nil
+}
+
+// isPersonalOrganization reports whether member is alone in an organization that has
+// never had a job, such as the one ensureMembership creates for a company profile.
+// Leaving it loses nothing, so it does not stand in the way of joining a team.
+func (u *organizationUsecase) isPersonalOrganization(ctx context.Context, member *domain.OrganizationMember) (bool, error) {
+	members, err := u.orgRepo.CountMembers(ctx, member.OrganizationID)
+	if err != nil || members != 1 {
+		return false, err
+	}
+	hasJobs, err := u.orgRepo.HasJobs(ctx, member.OrganizationID)
+	if err != nil {
+		return false, err
+	}
+	return !hasJobs, nil
+}
+
+// ensureAnotherOwner stops the last owner from being demoted or removed, which would
+// leave nobody able to manage the organization.
+func (u *organizationUsecase) ensureAnotherOwner(ctx context.Context, orgID uuid.UUID) error {
+	owners, err := u.orgRepo.CountOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return domain.ErrBadRequest
+	}
+	return nil
+}
+
+// withMembership attaches the actor's organization membership for policy checks.
+func withMembership(ctx context.Context, orgRepo domain.OrganizationRepository, actor domain.Actor) (domain.Actor, error) {
+	if actor.Membership != nil || actor.Role != domain.RoleRecruiter {
+		return actor, nil
+	}
+	member, err := orgRepo.GetMembership(ctx, actor.UserID)
+	if err != nil {
+		return actor, err
+	}
+	actor.Membership = member
+	return actor, nil
+}
+
+// ensureMembership is withMembership for recruiters who are about to create something
+// their organization will own. A recruiter working alone gets a personal organization,
+// so posting jobs keeps working without setting up a team first.
+func ensureMembership(ctx context.Context, orgRepo domain.OrganizationRepository, userRepo domain.UserRepository, actor domain.Actor) (domain.Actor, error) {
+	actor, err := withMembership(ctx, orgRepo, actor)
+	if err != nil || actor.Membership != nil || actor.Role != domain.RoleRecruiter {
+		return actor, err
+	}
+
+	user, err := userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return actor, err
+	}
+	org := &domain.Organization{Name: user.Email}
+	member := &domain.OrganizationMember{UserID: actor.UserID, Role: domain.OrgRoleOwner}
+	if err := orgRepo.Create(ctx, org, member); err != nil {
+		// A concurrent request may have just created it; the unique user index lets only one win.
+		if existing, getErr := orgRepo.GetMembership(ctx, actor.UserID); getErr == nil && existing != nil {
+			actor.Membership = existing
+			return actor, nil
+		}
+		return actor, err
+	}
+	member.Organization = org
+	actor.Membership = member
+	return actor, nil
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"be-job-portal/internal/config"
+	"be-job-portal/internal/domain"
+	"be-job-portal/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+func newMember(orgID uuid.UUID, role string) *domain.OrganizationMember {
+	return &domain.OrganizationMember{ID: uuid.New(), OrganizationID: orgID, UserID: uuid.New(), Role: role}
+}
+
+func recruiterActor(member *domain.OrganizationMember) domain.Actor {
+	return domain.Actor{UserID: member.UserID, Role: domain.RoleRecruiter}
+}
+
+func TestRemoveMemberSoleOwnerDissolves(t *testing.T) {
+	ctx := context.Background()
+	owner := newMember(uuid.New(), domain.OrgRoleOwner)
+	orgs := newFakeOrgRepo(owner)
+	u := NewOrganizationUsecase(orgs, nil, nil, config.Config{})
+
+	if err := u.RemoveMember(ctx, recruiterActor(owner), owner.ID); err != nil {
+		t.Fatalf("sole owner leaving: %v", err)
+	}
+	if len(orgs.dissolved) != 1 || orgs.dissolved[0] != owner.OrganizationID {
+		t.Fatalf("dissolved = %v, want [%s]", orgs.dissolved, owner.OrganizationID)
+	}
+}
+
+func TestRemoveMemberLastOwnerWithTeammates(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+	owner := newMember(orgID, domain.OrgRoleOwner)
+	teammate := newMember(orgID, domain.OrgRoleRecruiter)
+	orgs := newFakeOrgRepo(owner, teammate)
+	u := NewOrganizationUsecase(orgs, nil, nil, config.Config{})
+
+	if err := u.RemoveMember(ctx, recruiterActor(owner), owner.ID); !errors.Is(err, domain.ErrBadRequest) {
+		t.Fatalf("err = %v, want ErrBadRequest", err)
+	}
+	if _, ok := orgs.members[owner.ID]; !ok || len(orgs.dissolved) != 0 {
+		t.Fatal("the last owner left a team that still has members")
+	}
+
+	// The teammate can still leave on their own.
+	if err := u.RemoveMember(ctx, recruiterActor(teammate), teammate.ID); err != nil {
+		t.Fatalf("teammate leaving: %v", err)
+	}
+	if _, ok := orgs.members[teammate.ID]; ok || len(orgs.dissolved) != 0 {
+		t.Fatal("teammate leaving should remove only their membership")
+	}
+}
+
+func TestAcceptInvitationLeavesPersonalOrganization(t *testing.T) {
+	tests := []struct {
+		name     string
+		teammate bool
+		hasJobs  bool
+		wantErr  error
+	}{
+		{name: "untouched personal organization"},
+		{name: "personal organization with jobs", hasJobs: true, wantErr: domain.ErrConflict},
+		{name: "team with other members", teammate: true, wantErr: domain.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			current := newMember(uuid.New(), domain.OrgRoleOwner)
+			orgs := newFakeOrgRepo(current)
+			if tt.teammate {
+				teammate := newMember(current.OrganizationID, domain.OrgRoleOwner)
+				orgs.members[teammate.ID] = teammate
+			}
+			orgs.orgJobs[current.OrganizationID] = tt.hasJobs
+
+			invitedOrg := uuid.New()
+			orgs.invitations[utils.HashToken("invite")] = &domain.OrganizationInvitation{
+				ID:             uuid.New(),
+				OrganizationID: invitedOrg,
+				Email:          "ada@example.com",
+				Role:           domain.OrgRoleRecruiter,
+				ExpiresAt:      time.Now().Add(time.Hour),
+			}
+			users := newFakeUserRepo(&domain.User{ID: current.UserID, Email: "Ada@example.com", Role: domain.RoleRecruiter})
+			u := NewOrganizationUsecase(orgs, users, nil, config.Config{})
+
+			member, err := u.AcceptInvitation(ctx, recruiterActor(current), "invite")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if len(orgs.dissolved) != 0 {
+					t.Fatal("dissolved an organization that was not personal")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if member.OrganizationID != invitedOrg || len(orgs.dissolved) != 1 || orgs.dissolved[0] != current.OrganizationID {
+				t.Fatalf("member org = %s, dissolved = %v", member.OrganizationID, orgs.dissolved)
+			}
+		})
+	}
+}
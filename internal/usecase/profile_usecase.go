@@ -13,30 +13,42 @@ import (
 
 type profileUsecase struct {
 	profileRepo domain.ProfileRepository
+	orgRepo     domain.OrganizationRepository
+	userRepo    domain.UserRepository
+	audit       domain.AuditLogger
 }
 
-func NewProfileUsecase(profileRepo domain.ProfileRepository) domain.ProfileUsecase {
-	return &profileUsecase{profileRepo}
+func NewProfileUsecase(profileRepo domain.ProfileRepository, orgRepo domain.OrganizationRepository, userRepo domain.UserRepository, audit domain.AuditLogger) domain.ProfileUsecase {
+	return &profileUsecase{profileRepo, orgRepo, userRepo, audit}
 }
 
-func (u *profileUsecase) GetProfile(ctx context.Context, userID uuid.UUID, role string) (interface{}, error) {
-	switch strings.ToUpper(role) {
-	case "SEEKER":
-		profile, err := u.profileRepo.GetSeekerProfile(ctx, userID)
+func (u *profileUsecase) GetProfile(ctx context.Context, actor domain.Actor) (interface{}, error) {
+	switch strings.ToUpper(actor.Role) {
+	case domain.RoleSeeker:
+		profile, err := u.profileRepo.GetSeekerProfile(ctx, actor.UserID)
 		if err != nil {
 			return nil, err
 		}
 		if profile == nil {
-			return &domain.SeekerProfile{UserID: userID}, nil
+			return &domain.SeekerProfile{UserID: actor.UserID}, nil
 		}
 		return profile, nil
-	case "RECRUITER":
-		profile, err := u.profileRepo.GetCompanyProfile(ctx, userID)
+	case domain.RoleRecruiter:
+		actor, err := withMembership(ctx, u.orgRepo, actor)
+		if err != nil {
+			return nil, err
+		}
+		if actor.Membership == nil {
+			return &domain.CompanyProfile{UserID: actor.UserID}, nil
+		}
+
+		orgID := actor.Membership.OrganizationID
+		profile, err := u.profileRepo.GetCompanyProfile(ctx, orgID)
 		if err != nil {
 			return nil, err
 		}
 		if profile == nil {
-			return &domain.CompanyProfile{UserID: userID}, nil
+			return &domain.CompanyProfile{UserID: actor.UserID, OrganizationID: &orgID}, nil
 		}
 		return profile, nil
 	}
@@ -44,11 +56,69 @@ func (u *profileUsecase) GetProfile(ctx context.Context, userID uuid.UUID, role
 }
 
 func (u *profileUsecase) UpdateSeekerProfile(ctx context.Context, userID uuid.UUID, profile *domain.SeekerProfile) error {
+	before, err := u.profileRepo.GetSeekerProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		before = &domain.SeekerProfile{}
+	}
+
 	profile.UserID = userID
-	return u.profileRepo.UpdateSeekerProfile(ctx, profile)
+	if err := u.profileRepo.UpdateSeekerProfile(ctx, profile); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditProfileUpdated,
+		ActorID:    &userID,
+		TargetType: domain.AuditTargetSeekerProfile,
+		TargetID:   &profile.ID,
+		Changes:    auditDiff(before, profile, seekerProfileAuditFields...),
+	})
+	return nil
 }
 
-func (u *profileUsecase) UpdateCompanyProfile(ctx context.Context, userID uuid.UUID, profile *domain.CompanyProfile) error {
-	profile.UserID = userID
-	return u.profileRepo.UpdateCompanyProfile(ctx, profile)
+// Profile fields whose edits are recorded in the audit trail. Experience and education
+// entries are left out; they are replaced wholesale on every save.
+var (
+	seekerProfileAuditFields  = []string{"full_name", "phone", "address", "resume_url", "portfolio_url", "linkedin_url", "description", "skills"}
+	companyProfileAuditFields = []string{"company_name", "website", "phone", "location", "description", "logo_url"}
+)
+
+// UpdateCompanyProfile edits the profile of the recruiter's organization, which only
+// its owners and admins may do.
+func (u *profileUsecase) UpdateCompanyProfile(ctx context.Context, actor domain.Actor, profile *domain.CompanyProfile) error {
+	actor, err := ensureMembership(ctx, u.orgRepo, u.userRepo, actor)
+	if err != nil {
+		return err
+	}
+	if err := domain.Authorize(actor, domain.ActionManageOrganization, nil); err != nil {
+		return err
+	}
+
+	orgID := actor.Membership.OrganizationID
+	before, err := u.profileRepo.GetCompanyProfile(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		before = &domain.CompanyProfile{}
+	}
+
+	profile.UserID = actor.UserID
+	profile.OrganizationID = &orgID
+	if err := u.profileRepo.UpdateCompanyProfile(ctx, profile); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, u.audit, domain.AuditEvent{
+		Action:     domain.AuditProfileUpdated,
+		ActorID:    &actor.UserID,
+		TargetType: domain.AuditTargetCompanyProfile,
+		TargetID:   &profile.ID,
+		Changes:    auditDiff(before, profile, companyProfileAuditFields...),
+		Metadata:   map[string]string{"organization_id": orgID.String()},
+	})
+	return nil
 }
@@ -0,0 +1,21 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+)
+
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New returns the sender for the configured driver. Only development drivers exist for now.
+func New(driver, filePath string) (Sender, error) {
+	switch driver {
+	case "", "log":
+		return NewLogSender(), nil
+	case "file":
+		return NewFileSender(filePath), nil
+	}
+	return nil, fmt.Errorf("unknown mail driver %q", driver)
+}
@@ -0,0 +1,257 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"be-job-portal/pkg/jwk"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "client-123"
+	testKid      = "key-1"
+)
+
+// fakeProvider serves a token endpoint and JWKS the way a real issuer would. The
+// token endpoint only accepts the expected code and PKCE verifier, and answers with
+// whatever ID token the test prepared.
+type fakeProvider struct {
+	server   *httptest.Server
+	key      ed25519.PrivateKey
+	code     string
+	verifier string
+	idToken  string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwkKey, err := jwk.FromPublicKey(pub, testKid, "EdDSA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("code") != f.code || r.PostForm.Get("code_verifier") != f.verifier {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     f.idToken,
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(jwk.Set{Keys: []jwk.Key{jwkKey}})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeProvider) issuer() string {
+	return f.server.URL
+}
+
+func (f *fakeProvider) provider() *Provider {
+	return NewProvider(Config{
+		Name:        "fake",
+		Issuer:      f.issuer(),
+		ClientID:    testClientID,
+		RedirectURL: "https://app.example.com/api/auth/fake/callback",
+		AuthURL:     f.server.URL + "/authorize",
+		TokenURL:    f.server.URL + "/token",
+		JWKSURL:     f.server.URL + "/jwks",
+	}, f.server.Client())
+}
+
+func (f *fakeProvider) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = testKid
+	signed, err := token.SignedString(f.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (f *fakeProvider) claims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            f.issuer(),
+		"aud":            testClientID,
+		"sub":            "user-42",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "Ada@Example.com",
+		"email_verified": true,
+		"name":           "Ada",
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	f := newFakeProvider(t)
+
+	raw, err := f.provider().AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := authURL.Scheme + "://" + authURL.Host + authURL.Path; got != f.server.URL+"/authorize" {
+		t.Fatalf("authorize endpoint = %s", got)
+	}
+
+	sum := sha256.Sum256([]byte("verifier-1"))
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          "https://app.example.com/api/auth/fake/callback",
+		"scope":                 "openid email profile",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(sum[:]),
+		"code_challenge_method": "S256",
+	}
+	query := authURL.Query()
+	for param, value := range want {
+		if got := query.Get(param); got != value {
+			t.Errorf("%s = %q, want %q", param, got, value)
+		}
+	}
+	if query.Has("code_verifier") {
+		t.Error("the PKCE verifier leaked into the authorize URL")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		nonce   string
+		mutate  func(claims jwt.MapClaims)
+		wantErr string
+	}{
+		{name: "valid id token"},
+		{name: "wrong code", code: "stolen", wantErr: "invalid_grant"},
+		{name: "nonce mismatch", nonce: "other-nonce", wantErr: "nonce mismatch"},
+		{
+			name:    "wrong issuer",
+			mutate:  func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "wrong audience",
+			mutate:  func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "expired",
+			mutate:  func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "missing subject",
+			mutate:  func(claims jwt.MapClaims) { delete(claims, "sub") },
+			wantErr: "no subject",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeProvider(t)
+			f.code, f.verifier = "code-1", "verifier-1"
+			claims := f.claims("nonce-1")
+			if tt.mutate != nil {
+				tt.mutate(claims)
+			}
+			f.idToken = f.sign(t, claims)
+
+			code, nonce := "code-1", "nonce-1"
+			if tt.code != "" {
+				code = tt.code
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			identity, err := f.provider().Exchange(context.Background(), code, "verifier-1", nonce)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Identity{Subject: "user-42", Email: "ada@example.com", EmailVerified: true, Name: "Ada"}
+			if *identity != want {
+				t.Fatalf("identity = %+v, want %+v", *identity, want)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsForeignSigningKey(t *testing.T) {
+	f := newFakeProvider(t)
+	f.code, f.verifier = "code-1", "verifier-1"
+
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, f.claims("nonce-1"))
+	token.Header["kid"] = testKid
+	if f.idToken, err = token.SignedString(other); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.provider().Exchange(context.Background(), "code-1", "verifier-1", "nonce-1"); err == nil {
+		t.Fatal("accepted an id token signed with a key outside the JWKS")
+	}
+}
+
+func TestExplicitEndpointsRequireIssuer(t *testing.T) {
+	f := newFakeProvider(t)
+	p := NewProvider(Config{
+		Name:     "fake",
+		ClientID: testClientID,
+		AuthURL:  f.server.URL + "/authorize",
+		TokenURL: f.server.URL + "/token",
+		JWKSURL:  f.server.URL + "/jwks",
+	}, f.server.Client())
+
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil || !strings.Contains(err.Error(), "no issuer") {
+		t.Fatalf("err = %v, want a missing issuer error", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package scheduler runs periodic tasks on a single instance of the API. Instances elect
+// a leader with a Postgres session-level advisory lock held on a dedicated connection;
+// if the leader dies, Postgres releases the lock and another instance takes over on its
+// next tick.
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+type Task struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	db       *sql.DB
+	lockKey  int64
+	interval time.Duration
+	tasks    []Task
+
+	// conn holds the advisory lock while this instance is the leader.
+	conn *sql.Conn
+}
+
+// New returns a scheduler that runs tasks in order every interval. Instances sharing a
+// database must use the same lockKey.
+func New(db *sql.DB, lockKey int64, interval time.Duration, tasks ...Task) *Scheduler {
+	return &Scheduler{db: db, lockKey: lockKey, interval: interval, tasks: tasks}
+}
+
+// Start runs the scheduler in the background until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	go s.loop(ctx)
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer s.resign()
+
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	leader, err := s.lead(ctx)
+	if err != nil {
+		log.Printf("scheduler: leader election failed: %v", err)
+		return
+	}
+	if !leader {
+		return
+	}
+
+	for _, task := range s.tasks {
+		if err := task.Run(ctx); err != nil {
+			log.Printf("scheduler: %s failed: %v", task.Name, err)
+		}
+	}
+}
+
+// lead reports whether this instance is the leader, trying to take the lock if not.
+func (s *Scheduler) lead(ctx context.Context) (bool, error) {
+	if s.conn != nil {
+		// The lock lives exactly as long as the session that took it.
+		if err := s.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", s.lockKey).Scan(&locked); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+
+	s.conn = conn
+	return true, nil
+}
+
+// resign releases the lock before the connection goes back to the pool, where it
+// would otherwise keep holding it.
+func (s *Scheduler) resign() {
+	if s.conn == nil {
+		return
+	}
+	if _, err := s.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", s.lockKey); err != nil {
+		log.Printf("scheduler: releasing leadership failed: %v", err)
+	}
+	s.conn.Close()
+	s.conn = nil
+}
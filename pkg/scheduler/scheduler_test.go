@@ -0,0 +1,302 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockServer plays the part of Postgres for advisory locks: a lock belongs to the
+// session (driver connection) that took it until that session unlocks it or ends.
+type lockServer struct {
+	mu    sync.Mutex
+	locks map[int64]*fakeConn
+}
+
+func newLockServer() *lockServer {
+	return &lockServer{locks: make(map[int64]*fakeConn)}
+}
+
+func (s *lockServer) open() *sql.DB {
+	return sql.OpenDB(&fakeConnector{server: s})
+}
+
+// holder returns the session holding key, or nil.
+func (s *lockServer) holder(key int64) *fakeConn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locks[key]
+}
+
+// terminate ends a session the way a crash or network failure would.
+func (s *lockServer) terminate(conn *fakeConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn.dead = true
+	s.releaseAll(conn)
+}
+
+func (s *lockServer) releaseAll(conn *fakeConn) {
+	for key, holder := range s.locks {
+		if holder == conn {
+			delete(s.locks, key)
+		}
+	}
+}
+
+type fakeConnector struct {
+	server *lockServer
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{server: c.server}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("use the connector")
+}
+
+type fakeConn struct {
+	server *lockServer
+	dead   bool
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	c.server.releaseAll(c)
+	return nil
+}
+
+func (c *fakeConn) IsValid() bool {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	return !c.dead
+}
+
+func (c *fakeConn) Ping(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.dead {
+		return nil, driver.ErrBadConn
+	}
+	if !strings.Contains(query, "pg_try_advisory_lock") {
+		return nil, errors.New("unexpected query: " + query)
+	}
+
+	key := args[0].Value.(int64)
+	holder, held := s.locks[key]
+	if !held {
+		s.locks[key] = c
+	}
+	return &boolRows{value: !held || holder == c}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.dead {
+		return nil, driver.ErrBadConn
+	}
+	if !strings.Contains(query, "pg_advisory_unlock") {
+		return nil, errors.New("unexpected statement: " + query)
+	}
+
+	key := args[0].Value.(int64)
+	if s.locks[key] == c {
+		delete(s.locks, key)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+type boolRows struct {
+	value bool
+	done  bool
+}
+
+func (r *boolRows) Columns() []string { return []string{"locked"} }
+
+func (r *boolRows) Close() error { return nil }
+
+func (r *boolRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+const testLockKey = 42
+
+// countingScheduler returns a scheduler on db whose single task counts its runs.
+func countingScheduler(db *sql.DB) (*Scheduler, *int) {
+	runs := 0
+	s := New(db, testLockKey, time.Hour, Task{Name: "count", Run: func(ctx context.Context) error {
+		runs++
+		return nil
+	}})
+	return s, &runs
+}
+
+func TestOnlyOneInstanceLeads(t *testing.T) {
+	ctx := context.Background()
+	server := newLockServer()
+	db := server.open()
+	defer db.Close()
+
+	a, aRuns := countingScheduler(db)
+	b, bRuns := countingScheduler(db)
+	defer a.resign()
+	defer b.resign()
+
+	for i := 0; i < 3; i++ {
+		a.tick(ctx)
+		b.tick(ctx)
+	}
+	if *aRuns != 3 || *bRuns != 0 {
+		t.Fatalf("runs: a = %d, b = %d; want only the first instance to run, every tick", *aRuns, *bRuns)
+	}
+}
+
+func TestLeadershipMovesWhenTheLeaderDies(t *testing.T) {
+	ctx := context.Background()
+	server := newLockServer()
+	db := server.open()
+	defer db.Close()
+
+	a, aRuns := countingScheduler(db)
+	b, bRuns := countingScheduler(db)
+	defer b.resign()
+
+	a.tick(ctx)
+	leaderSession := server.holder(testLockKey)
+	if leaderSession == nil {
+		t.Fatal("the first instance did not take the lock")
+	}
+
+	server.terminate(leaderSession)
+	b.tick(ctx)
+	if *bRuns != 1 {
+		t.Fatalf("b ran %d times after the leader's session ended, want 1", *bRuns)
+	}
+
+	// The old leader notices its session is gone and stays a follower.
+	a.tick(ctx)
+	if *aRuns != 1 {
+		t.Fatalf("a ran %d times, want only its first tick", *aRuns)
+	}
+	if a.conn != nil {
+		t.Fatal("the old leader kept its dead connection")
+	}
+}
+
+func TestResignReleasesTheLock(t *testing.T) {
+	ctx := context.Background()
+	server := newLockServer()
+	db := server.open()
+	defer db.Close()
+
+	a, _ := countingScheduler(db)
+	b, bRuns := countingScheduler(db)
+	defer b.resign()
+
+	a.tick(ctx)
+	a.resign()
+	// The connection went back to the pool; the session, and so the lock, must not
+	// outlive the leadership.
+	if server.holder(testLockKey) != nil {
+		t.Fatal("resigning left the advisory lock held by a pooled connection")
+	}
+
+	b.tick(ctx)
+	if *bRuns != 1 {
+		t.Fatalf("b ran %d times after a resigned, want 1", *bRuns)
+	}
+}
+
+func TestFailingTaskDoesNotStopTheOthers(t *testing.T) {
+	ctx := context.Background()
+	server := newLockServer()
+	db := server.open()
+	defer db.Close()
+
+	var ran []string
+	s := New(db, testLockKey, time.Hour,
+		Task{Name: "broken", Run: func(ctx context.Context) error {
+			ran = append(ran, "broken")
+			return errors.New("boom")
+		}},
+		Task{Name: "healthy", Run: func(ctx context.Context) error {
+			ran = append(ran, "healthy")
+			return nil
+		}},
+	)
+	defer s.resign()
+
+	s.tick(ctx)
+	if strings.Join(ran, ",") != "broken,healthy" {
+		t.Fatalf("ran = %v, want both tasks in order", ran)
+	}
+}
+
+func TestStartStopsWithContext(t *testing.T) {
+	server := newLockServer()
+	db := server.open()
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s := New(db, testLockKey, time.Millisecond, Task{Name: "tick", Run: func(ctx context.Context) error {
+		select {
+		case done <- struct{}{}:
+		default:
+		}
+		return nil
+	}})
+	s.Start(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the scheduler never ran its task")
+	}
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for server.holder(testLockKey) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("the lock is still held after the scheduler stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
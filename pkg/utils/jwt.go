@@ -1,28 +1,102 @@
 package utils
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// Token types carried in the JWT typ header so a challenge token can never be
+// replayed as an access token, including by services verifying against the JWKS.
+const (
+	accessTokenType    = "at+jwt"
+	challengeTokenType = "2fa-challenge+jwt"
+)
+
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Role   string    `json:"role"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"session_id"`
+	Role      string    `json:"role"`
+	// Act names the admin acting as the user during impersonation (RFC 8693).
+	Act *ActClaim `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uuid.UUID, role, secret string) (string, error) {
+type ActClaim struct {
+	Subject uuid.UUID `json:"sub"`
+}
+
+func GenerateToken(keys *KeySet, userID, sessionID uuid.UUID, role string, ttl time.Duration) (string, time.Time, error) {
+	return generateAccessToken(keys, userID, sessionID, role, nil, ttl)
+}
+
+// GenerateImpersonationToken issues an access token for userID that records impersonatorID in the act claim.
+func GenerateImpersonationToken(keys *KeySet, userID, sessionID uuid.UUID, role string, impersonatorID uuid.UUID, ttl time.Duration) (string, time.Time, error) {
+	return generateAccessToken(keys, userID, sessionID, role, &ActClaim{Subject: impersonatorID}, ttl)
+}
+
+func generateAccessToken(keys *KeySet, userID, sessionID uuid.UUID, role string, act *ActClaim, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		SessionID: sessionID,
+		Role:      role,
+		Act:       act,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	signed, err := keys.Sign(claims, accessTokenType)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseToken verifies an access token against every key in the set.
+func ParseToken(keys *KeySet, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := keys.Parse(tokenString, claims, accessTokenType); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+const challengePurpose = "2fa"
+
+// ChallengeClaims identify a user who passed the password step but still owes a second factor.
+type ChallengeClaims struct {
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+func GenerateChallengeToken(keys *KeySet, userID uuid.UUID, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &ChallengeClaims{
+		Purpose: challengePurpose,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   userID.String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	return keys.Sign(claims, challengeTokenType)
+}
+
+func ParseChallengeToken(keys *KeySet, tokenString string) (uuid.UUID, error) {
+	claims := &ChallengeClaims{}
+	if err := keys.Parse(tokenString, claims, challengeTokenType); err != nil {
+		return uuid.Nil, err
+	}
+	if claims.Purpose != challengePurpose {
+		return uuid.Nil, fmt.Errorf("unexpected token purpose %q", claims.Purpose)
+	}
+	return uuid.Parse(claims.Subject)
 }
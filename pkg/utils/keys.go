@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"be-job-portal/pkg/jwk"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type verificationKey struct {
+	key    crypto.PublicKey
+	method jwt.SigningMethod
+	jwk    jwk.Key
+}
+
+// KeySet signs tokens with a single active private key and accepts tokens signed by
+// any configured key. Rotating means publishing the new public key first, switching
+// the signing key, then dropping the old one once its tokens have expired.
+type KeySet struct {
+	signingKey    crypto.Signer
+	signingKid    string
+	signingMethod jwt.SigningMethod
+	verification  map[string]verificationKey
+}
+
+// NewKeySet builds a key set from an RSA or Ed25519 signing key and extra public keys
+// that should still be accepted, typically the previous signing keys.
+func NewKeySet(signingKey crypto.Signer, previous ...crypto.PublicKey) (*KeySet, error) {
+	ks := &KeySet{verification: make(map[string]verificationKey)}
+
+	kid, method, err := ks.add(signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	ks.signingKey = signingKey
+	ks.signingKid = kid
+	ks.signingMethod = method
+
+	for _, pub := range previous {
+		if _, _, err := ks.add(pub); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// LoadKeySet reads PEM encoded keys from disk. Verification files may hold either
+// public or private keys. Without a signing key path an ephemeral Ed25519 key is
+// generated, which is only suitable for a single development instance.
+func LoadKeySet(signingKeyPath string, verificationKeyPaths []string) (*KeySet, error) {
+	var signer crypto.Signer
+	if signingKeyPath == "" {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		signer = priv
+	} else {
+		key, err := readPEMKey(signingKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		if signer, ok = key.(crypto.Signer); !ok {
+			return nil, fmt.Errorf("%s does not contain a private key", signingKeyPath)
+		}
+	}
+
+	var previous []crypto.PublicKey
+	for _, path := range verificationKeyPaths {
+		key, err := readPEMKey(path)
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := key.(crypto.Signer); ok {
+			key = s.Public()
+		}
+		previous = append(previous, key)
+	}
+
+	return NewKeySet(signer, previous...)
+}
+
+// Sign returns the compact JWT for claims with the active kid and the given typ header.
+func (ks *KeySet) Sign(claims jwt.Claims, typ string) (string, error) {
+	token := jwt.NewWithClaims(ks.signingMethod, claims)
+	token.Header["kid"] = ks.signingKid
+	token.Header["typ"] = typ
+	return token.SignedString(ks.signingKey)
+}
+
+// Parse verifies the signature, standard time claims and typ header of tokenString.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims, typ string) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Header["typ"] != typ {
+			return nil, fmt.Errorf("unexpected token type %v", token.Header["typ"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		vk, ok := ks.verification[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if token.Method != vk.method {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return vk.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// JWKS lists every public key that tokens may currently be signed with.
+func (ks *KeySet) JWKS() jwk.Set {
+	set := jwk.Set{Keys: make([]jwk.Key, 0, len(ks.verification))}
+	set.Keys = append(set.Keys, ks.verification[ks.signingKid].jwk)
+	for kid, vk := range ks.verification {
+		if kid != ks.signingKid {
+			set.Keys = append(set.Keys, vk.jwk)
+		}
+	}
+	return set
+}
+
+func (ks *KeySet) add(pub crypto.PublicKey) (string, jwt.SigningMethod, error) {
+	var method jwt.SigningMethod
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return "", nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", pub)
+	}
+
+	key, err := jwk.FromPublicKey(pub, "", method.Alg())
+	if err != nil {
+		return "", nil, err
+	}
+	kid, err := jwk.Thumbprint(key)
+	if err != nil {
+		return "", nil, err
+	}
+	key.Kid = kid
+
+	ks.verification[kid] = verificationKey{key: pub, method: method, jwk: key}
+	return kid, method, nil
+}
+
+func readPEMKey(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestKeySetRotation(t *testing.T) {
+	oldKey := newEd25519Key(t)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := NewKeySet(oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := NewKeySet(newKey, oldKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropped, err := NewKeySet(newKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userID, sessionID := uuid.New(), uuid.New()
+	oldToken, _, err := GenerateToken(before, userID, sessionID, "SEEKER", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newToken, _, err := GenerateToken(after, userID, sessionID, "SEEKER", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		keys    *KeySet
+		token   string
+		wantErr bool
+	}{
+		{"old token, old key set", before, oldToken, false},
+		{"old token still accepted after rotation", after, oldToken, false},
+		{"new token after rotation", after, newToken, false},
+		{"new token unknown to old key set", before, newToken, true},
+		{"old token rejected once the old key is dropped", dropped, oldToken, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.keys, tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected the token to be rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseToken: %v", err)
+			}
+			if claims.UserID != userID || claims.SessionID != sessionID {
+				t.Fatalf("claims = %+v, want user %s session %s", claims, userID, sessionID)
+			}
+		})
+	}
+}
+
+func TestKeySetSelectsKeyByKid(t *testing.T) {
+	oldKey, newKey := newEd25519Key(t), newEd25519Key(t)
+	keys, err := NewKeySet(newKey, oldKey.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := &Claims{
+		UserID: uuid.New(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	sign := func(key ed25519.PrivateKey, kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		token.Header["kid"] = kid
+		token.Header["typ"] = accessTokenType
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	set := keys.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("JWKS has %d keys, want 2", len(set.Keys))
+	}
+	newKid, oldKid := set.Keys[0].Kid, set.Keys[1].Kid
+	if newKid != keys.signingKid {
+		t.Fatalf("JWKS lists %q first, want the signing key %q", newKid, keys.signingKid)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"signing key with its kid", sign(newKey, newKid), false},
+		{"previous key with its kid", sign(oldKey, oldKid), false},
+		{"previous key under the signing kid", sign(oldKey, newKid), true},
+		{"unknown kid", sign(newKey, "unknown"), true},
+		{"missing kid", sign(newKey, ""), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseToken(keys, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChallengeTokenIsNotAnAccessToken(t *testing.T) {
+	keys, err := NewKeySet(newEd25519Key(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := GenerateChallengeToken(keys, uuid.New(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseToken(keys, challenge); err == nil {
+		t.Fatal("a challenge token was accepted as an access token")
+	}
+}
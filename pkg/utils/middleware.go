@@ -1,52 +1,237 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"be-job-portal/internal/domain"
+
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/spf13/viper"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// SessionValidator checks that the session an access token was issued for has not been revoked.
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, sessionID uuid.UUID) error
+}
+
+// APIKeyAuthenticator resolves a plaintext API key to the key and the user it acts as.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error)
+}
+
+// AuthMiddleware accepts either a JWT access token or an API key, sent as a bearer token
+// or in X-API-Key. Both leave the same user_id and role in the context; key requests
+// also carry the key's scopes for RequireScope.
+func AuthMiddleware(keys *KeySet, sessions SessionValidator, apiKeys APIKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
-			return
+		tokenString := c.GetHeader("X-API-Key")
+		if tokenString == "" {
+			authHeader := c.GetHeader("Authorization")
+			if authHeader == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+				c.Abort()
+				return
+			}
+			tokenString = strings.Replace(authHeader, "Bearer ", "", 1)
 		}
 
-		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-		secret := viper.GetString("JWT_SECRET")
-
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		if strings.HasPrefix(tokenString, domain.APIKeyPrefix) {
+			key, err := apiKeys.Authenticate(c.Request.Context(), tokenString)
+			if err != nil {
+				if errors.Is(err, domain.ErrAccountSuspended) {
+					c.JSON(http.StatusForbidden, gin.H{"error": "Account has been suspended"})
+					c.Abort()
+					return
+				}
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+				c.Abort()
+				return
 			}
-			return []byte(secret), nil
-		})
 
+			c.Set("user_id", key.UserID)
+			c.Set("role", key.User.Role)
+			c.Set("api_key_id", key.ID)
+			c.Set("api_key_scopes", key.Scopes)
+			c.Next()
+			return
+		}
+
+		claims, err := ParseToken(keys, tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-			c.Set("user_id", claims.UserID)
-			c.Set("role", claims.Role)
+		if err := sessions.ValidateSession(c.Request.Context(), claims.SessionID); err != nil {
+			if errors.Is(err, domain.ErrAccountSuspended) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Account has been suspended"})
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has expired or been revoked"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
+		c.Set("role", claims.Role)
+		if claims.Act != nil {
+			c.Set("impersonator_id", claims.Act.Subject)
+			info := domain.RequestInfoFrom(c.Request.Context())
+			info.ImpersonatorID = &claims.Act.Subject
+			c.Request = c.Request.WithContext(domain.WithRequestInfo(c.Request.Context(), info))
+		}
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests whose token role is not one of roles. It must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		ErrorResponse(c, http.StatusForbidden, "Access denied", "Your role cannot access this resource")
+		c.Abort()
+	}
+}
+
+// RequireScope lets API keys through only if they were granted scope. Requests made with
+// a JWT are unaffected. It must run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, isAPIKey := c.Get("api_key_scopes")
+		if !isAPIKey {
 			c.Next()
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		for _, granted := range scopes.([]string) {
+			if granted == scope {
+				c.Next()
+				return
+			}
+		}
+
+		ErrorResponse(c, http.StatusForbidden, "Access denied", "API key is missing the "+scope+" scope")
+		c.Abort()
+	}
+}
+
+// RequireSession rejects API keys on routes meant for people signed in with a JWT,
+// such as account, organization and admin management. It must run after AuthMiddleware.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := c.Get("api_key_id"); isAPIKey {
+			ErrorResponse(c, http.StatusForbidden, "Access denied", "API keys cannot access this resource")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// DenyImpersonation blocks admins acting as a user from sensitive account actions such as
+// changing credentials. It must run after AuthMiddleware.
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonating := c.Get("impersonator_id"); impersonating {
+			ErrorResponse(c, http.StatusForbidden, "Access denied", "This action is not available while impersonating a user")
 			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AuditImpersonation records every request made with an impersonation token once it has
+// been handled, including rejected ones, with the admin as the actor and the impersonated
+// user in the metadata. Register it before the routes.
+func AuditImpersonation(logger domain.AuditLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		value, impersonating := c.Get("impersonator_id")
+		if !impersonating {
+			return
+		}
+		impersonatorID := value.(uuid.UUID)
+		userID, _ := GetUserID(c)
+		sessionID, _ := GetSessionID(c)
+
+		err := logger.Record(c.Request.Context(), domain.AuditEvent{
+			Action:         domain.AuditImpersonatedRequest,
+			ActorID:        &impersonatorID,
+			ImpersonatorID: &impersonatorID,
+			TargetType:     domain.AuditTargetSession,
+			TargetID:       &sessionID,
+			Metadata: map[string]string{
+				"impersonated_user_id": userID.String(),
+				"method":               c.Request.Method,
+				"path":                 c.Request.URL.Path,
+				"status":               strconv.Itoa(c.Writer.Status()),
+			},
+		})
+		if err != nil {
+			log.Printf("failed to record impersonated request: %v", err)
 		}
 	}
 }
 
+// maxRequestIDLength bounds client-supplied request IDs before they are stored.
+const maxRequestIDLength = 64
+
+// RequestID tags each request with an ID, reusing a sane X-Request-ID from the caller
+// or generating one, and echoes it in the response. The ID and client IP are put on the
+// request context for the audit trail. Register it before any other middleware.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Header("X-Request-ID", requestID)
+		c.Set("request_id", requestID)
+		ctx := domain.WithRequestInfo(c.Request.Context(), domain.RequestInfo{RequestID: requestID, IP: c.ClientIP()})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// GetActor returns the authenticated user and role for policy checks.
+func GetActor(c *gin.Context) (domain.Actor, error) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return domain.Actor{}, err
+	}
+	return domain.Actor{UserID: userID, Role: c.GetString("role")}, nil
+}
+
 // Helper to get UserID from context
 func GetUserID(c *gin.Context) (uuid.UUID, error) {
 	uid, exists := c.Get("user_id")
@@ -55,3 +240,12 @@ func GetUserID(c *gin.Context) (uuid.UUID, error) {
 	}
 	return uid.(uuid.UUID), nil
 }
+
+// Helper to get SessionID from context
+func GetSessionID(c *gin.Context) (uuid.UUID, error) {
+	sid, exists := c.Get("session_id")
+	if !exists {
+		return uuid.Nil, fmt.Errorf("session_id not found in context")
+	}
+	return sid.(uuid.UUID), nil
+}
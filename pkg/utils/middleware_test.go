@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-job-portal/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// runMiddleware sends one request through setup, which plays the part of
+// AuthMiddleware, and then middleware, reporting the status and whether the
+// final handler ran.
+func runMiddleware(setup, middleware gin.HandlerFunc) (int, bool) {
+	gin.SetMode(gin.TestMode)
+	reached := false
+	r := gin.New()
+	r.GET("/", setup, middleware, func(c *gin.Context) {
+		reached = true
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w.Code, reached
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		role    string
+		want    bool
+	}{
+		{name: "seeker on seeker route", allowed: []string{domain.RoleSeeker}, role: domain.RoleSeeker, want: true},
+		{name: "recruiter on seeker route", allowed: []string{domain.RoleSeeker}, role: domain.RoleRecruiter},
+		{name: "admin on seeker route", allowed: []string{domain.RoleSeeker}, role: domain.RoleAdmin},
+		{name: "recruiter on shared route", allowed: []string{domain.RoleRecruiter, domain.RoleAdmin}, role: domain.RoleRecruiter, want: true},
+		{name: "admin on shared route", allowed: []string{domain.RoleRecruiter, domain.RoleAdmin}, role: domain.RoleAdmin, want: true},
+		{name: "seeker on shared route", allowed: []string{domain.RoleRecruiter, domain.RoleAdmin}, role: domain.RoleSeeker},
+		{name: "missing role", allowed: []string{domain.RoleSeeker}},
+		{name: "role names are case sensitive", allowed: []string{domain.RoleAdmin}, role: "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := func(c *gin.Context) {
+				if tt.role != "" {
+					c.Set("role", tt.role)
+				}
+			}
+			code, reached := runMiddleware(setup, RequireRole(tt.allowed...))
+			if reached != tt.want {
+				t.Fatalf("reached handler = %v, want %v", reached, tt.want)
+			}
+			if !tt.want && code != http.StatusForbidden {
+				t.Fatalf("status = %d, want 403", code)
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		apiKey bool
+		want   bool
+	}{
+		{name: "session token", want: true},
+		{name: "key with the scope", apiKey: true, scopes: []string{domain.ScopeJobsRead, domain.ScopeJobsWrite}, want: true},
+		{name: "key with other scopes", apiKey: true, scopes: []string{domain.ScopeJobsRead}},
+		{name: "key without scopes", apiKey: true, scopes: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := func(c *gin.Context) {
+				if tt.apiKey {
+					c.Set("api_key_scopes", tt.scopes)
+				}
+			}
+			code, reached := runMiddleware(setup, RequireScope(domain.ScopeJobsWrite))
+			if reached != tt.want {
+				t.Fatalf("reached handler = %v, want %v", reached, tt.want)
+			}
+			if !tt.want && code != http.StatusForbidden {
+				t.Fatalf("status = %d, want 403", code)
+			}
+		})
+	}
+}
+
+type recordingLogger struct {
+	events []domain.AuditEvent
+}
+
+func (l *recordingLogger) Record(ctx context.Context, event domain.AuditEvent) error {
+	l.events = append(l.events, event)
+	return nil
+}
+
+func TestAuditImpersonationNamesTheAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	adminID, userID, sessionID := uuid.New(), uuid.New(), uuid.New()
+	logger := &recordingLogger{}
+
+	r := gin.New()
+	r.Use(AuditImpersonation(logger))
+	r.GET("/api/profile", func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("session_id", sessionID)
+		c.Set("impersonator_id", adminID)
+		c.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/profile", nil))
+
+	if len(logger.events) != 1 {
+		t.Fatalf("recorded %d events, want 1", len(logger.events))
+	}
+	event := logger.events[0]
+	if event.Action != domain.AuditImpersonatedRequest || event.ActorID == nil || *event.ActorID != adminID {
+		t.Fatalf("event %s by %v, want %s by the admin %s", event.Action, event.ActorID, domain.AuditImpersonatedRequest, adminID)
+	}
+	if got := event.Metadata["impersonated_user_id"]; got != userID.String() {
+		t.Fatalf("impersonated_user_id = %q, want %s", got, userID)
+	}
+	if event.TargetID == nil || *event.TargetID != sessionID || event.Metadata["status"] != "200" {
+		t.Fatalf("event = %+v", event)
+	}
+}
+
+func TestAuditImpersonationIgnoresRegularRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := &recordingLogger{}
+
+	r := gin.New()
+	r.Use(AuditImpersonation(logger))
+	r.GET("/api/profile", func(c *gin.Context) {
+		c.Set("user_id", uuid.New())
+		c.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/profile", nil))
+
+	if len(logger.events) != 0 {
+		t.Fatalf("recorded %v for a request without impersonation", logger.events)
+	}
+}